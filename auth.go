@@ -0,0 +1,339 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	. "memories/model"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	sessionCookie   = "session"
+	sessionDuration = 30 * 24 * time.Hour
+	userKey         = "user"
+	sessionKey      = "session"
+	daoKey          = "dao"
+)
+
+// argon2id parameters from RFC 9106's second recommended option. They're stored with each
+// hash so they can be raised later without breaking existing passwords.
+const (
+	argon2Time    = 3
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	argon2KeyLen  = 32
+	argon2SaltLen = 16
+)
+
+// Each password hash takes argon2Memory, so only a few run at once and password checks are
+// limited per client and per account on each client
+const (
+	concurrentHashes   = 4
+	attemptsPerIP      = 10
+	attemptWindow      = time.Minute
+	failuresPerAccount = 5
+	failureWindow      = 15 * time.Minute
+)
+
+// publicPaths can be reached without signing in
+var publicPaths = []string{"/login", "/style.css", "/favicon.ico", "/api/auth/login", "/api/auth/register"}
+
+// errTooManyAttempts is returned when a client has tried too many passwords lately, in all or for one account
+var errTooManyAttempts = errors.New("too many attempts")
+
+var (
+	hashSlots      = make(chan struct{}, concurrentHashes)
+	ipAttempts     = newRateLimiter(attemptsPerIP, attemptWindow)
+	failedAccounts = newRateLimiter(failuresPerAccount, failureWindow)
+)
+
+// rateLimiter counts events per key in fixed windows
+type rateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	counts map[string]*windowCount
+}
+
+type windowCount struct {
+	start time.Time
+	n     int
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{limit: limit, window: window, counts: make(map[string]*windowCount)}
+}
+
+// count returns key's window, starting a new one if the last has passed
+func (l *rateLimiter) count(key string, now time.Time) *windowCount {
+	count, ok := l.counts[key]
+	if ok && now.Sub(count.start) < l.window {
+		return count
+	}
+
+	// Forget finished windows now and then so the map doesn't grow without bound
+	if len(l.counts) >= 1024 {
+		for k, c := range l.counts {
+			if now.Sub(c.start) >= l.window {
+				delete(l.counts, k)
+			}
+		}
+	}
+	count = &windowCount{start: now}
+	l.counts[key] = count
+	return count
+}
+
+// full reports whether key has used up its window
+func (l *rateLimiter) full(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.count(key, time.Now()).n >= l.limit
+}
+
+// add records an event for key, reporting whether it was within the limit
+func (l *rateLimiter) add(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	count := l.count(key, time.Now())
+	count.n++
+	return count.n <= l.limit
+}
+
+// argon2Key derives an argon2id key, waiting for a free slot so hashes can't use up the memory
+func argon2Key(password, salt []byte, iterations, memory uint32, threads uint8, keyLen uint32) []byte {
+	hashSlots <- struct{}{}
+	defer func() { <-hashSlots }()
+	return argon2.IDKey(password, salt, iterations, memory, threads, keyLen)
+}
+
+// hashPassword hashes a password with argon2id and a new random salt, both base64 encoded
+func hashPassword(password string) (string, string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2Key([]byte(password), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+	hash := fmt.Sprintf("argon2id$v=%d$m=%d,t=%d,p=%d$%s", argon2.Version, argon2Memory, argon2Time, argon2Threads,
+		base64.RawStdEncoding.EncodeToString(key))
+	return hash, base64.RawStdEncoding.EncodeToString(salt), nil
+}
+
+// checkPassword reports whether password matches a hash made by hashPassword
+func checkPassword(password, hash, encodedSalt string) bool {
+	var version int
+	var memory, iterations uint32
+	var threads uint8
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 || parts[0] != "argon2id" {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[1], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[2], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(encodedSalt)
+	if err != nil {
+		return false
+	}
+	want, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return false
+	}
+	got := argon2Key([]byte(password), salt, iterations, memory, threads, uint32(len(want)))
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+// dummyPassword is checked against when an email isn't registered, so signing in takes
+// as long whether or not the account exists
+var dummyPassword = func() [2]string {
+	hash, salt, err := hashPassword("not a real password")
+	if err != nil {
+		log.Fatalf("Could not hash dummy password: %s", err)
+	}
+	return [2]string{hash, salt}
+}()
+
+// authenticate returns the user with the given email and password, or ErrNotFound if either is
+// wrong. It returns errTooManyAttempts without checking when ip has tried too often, in all or
+// for this account. Failures only count against the account from the client that made them,
+// so nobody else can lock its owner out.
+func authenticate(dao LifeJournalDAO, ip, email, password string) (*User, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	account := ip + " " + email
+	if !ipAttempts.add(ip) || failedAccounts.full(account) {
+		return nil, errTooManyAttempts
+	}
+
+	user, err := dao.GetUserByEmail(email)
+	if errors.Is(err, ErrNotFound) {
+		checkPassword(password, dummyPassword[0], dummyPassword[1])
+		failedAccounts.add(account)
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !checkPassword(password, user.PasswordHash, user.Salt) {
+		failedAccounts.add(account)
+		return nil, ErrNotFound
+	}
+	return user, nil
+}
+
+// claimDataOwnerRows gives everything saved before there were user accounts to the user with
+// the given email, once they've registered
+func claimDataOwnerRows(dao LifeJournalDAO, email string) error {
+	user, err := dao.GetUserByEmail(email)
+	if errors.Is(err, ErrNotFound) {
+		log.Printf("Anything saved before there were accounts will go to %s once they register", email)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	claimed, err := dao.ClaimUnownedRows(user.UUID)
+	if err != nil {
+		return err
+	}
+	if claimed > 0 {
+		log.Printf("Gave %d rows saved before there were accounts to %s", claimed, email)
+	}
+	return nil
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// hashSessionToken is what's stored for a session, so a leaked database can't be used to sign in
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// startSession signs a user in, setting an HTTP-only session cookie
+func startSession(c *gin.Context, dao LifeJournalDAO, user *User, secure bool) error {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Errorf("failed to generate session token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	err := dao.CreateSession(hashSessionToken(token), user.UUID, time.Now().Add(sessionDuration))
+	if err != nil {
+		return err
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, token, int(sessionDuration.Seconds()), "/", "", secure, true)
+	return nil
+}
+
+// endSession signs the current session out and clears its cookie
+func endSession(c *gin.Context, dao LifeJournalDAO, secure bool) error {
+	if token, err := c.Cookie(sessionCookie); err == nil {
+		if err := dao.DeleteSession(hashSessionToken(token)); err != nil {
+			return err
+		}
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, "", -1, "/", "", secure, true)
+	return nil
+}
+
+// currentUser is the user signed in for a request that passed requireAuth
+func currentUser(c *gin.Context) *User {
+	return c.MustGet(userKey).(*User)
+}
+
+// userDAO reads and writes only the data owned by the user signed in for a request
+func userDAO(c *gin.Context) LifeJournalDAO {
+	return c.MustGet(daoKey).(LifeJournalDAO)
+}
+
+// requireAuth lets requests through only when they're signed in with a session cookie, or
+// for calendar feeds with HTTP basic auth, since calendar apps can't keep cookies. Signed out
+// API requests get a 401 and page requests are sent to the login page.
+func requireAuth(dao LifeJournalDAO) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if slices.Contains(publicPaths, c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		if token, err := c.Cookie(sessionCookie); err == nil {
+			tokenHash := hashSessionToken(token)
+			user, err := dao.GetSessionUser(tokenHash)
+			if err == nil {
+				c.Set(userKey, user)
+				c.Set(sessionKey, tokenHash)
+				c.Set(daoKey, dao.ForUser(user.UUID))
+				c.Next()
+				return
+			}
+			if !errors.Is(err, ErrNotFound) {
+				log.Printf("Could not get session: %v", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check session"})
+				return
+			}
+		}
+
+		path := c.Request.URL.Path
+		if email, password, ok := c.Request.BasicAuth(); ok && strings.HasPrefix(path, "/calendar/") {
+			user, err := authenticate(dao, c.ClientIP(), email, password)
+			if err == nil {
+				c.Set(userKey, user)
+				c.Set(daoKey, dao.ForUser(user.UUID))
+				c.Next()
+				return
+			}
+			if errors.Is(err, errTooManyAttempts) {
+				c.AbortWithStatus(http.StatusTooManyRequests)
+				return
+			}
+			if !errors.Is(err, ErrNotFound) {
+				log.Printf("Could not check credentials: %v", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check credentials"})
+				return
+			}
+		}
+
+		switch {
+		case strings.HasPrefix(path, "/calendar/"):
+			c.Header("WWW-Authenticate", `Basic realm="memories", charset="UTF-8"`)
+			c.AbortWithStatus(http.StatusUnauthorized)
+		case strings.HasPrefix(path, "/api/") || c.Request.Method != http.MethodGet:
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Sign in required"})
+		default:
+			c.Redirect(http.StatusSeeOther, "/login?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"memories/daos"
+	"path/filepath"
+	"testing"
+
+	. "memories/model"
+)
+
+func TestFailedPasswordsOnlyLockOutTheirClient(t *testing.T) {
+	db := daos.InitSQLiteDB(filepath.Join(t.TempDir(), "test.sqlite"))
+	t.Cleanup(func() { db.Close() })
+	dao := daos.NewSQLiteDAO(db)
+
+	hash, salt, err := hashPassword("password123")
+	if err != nil {
+		t.Fatalf("hashPassword: %v", err)
+	}
+	err = dao.CreateUser(User{UUID: "aaaaaaaa-0000-4000-8000-000000000000", Email: "limits@example.com", PasswordHash: hash, Salt: salt})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	const attacker, owner = "203.0.113.1", "203.0.113.2"
+	for i := 0; i < failuresPerAccount; i++ {
+		_, err := authenticate(dao, attacker, "limits@example.com", "wrong password")
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("wrong password %d = %v, want ErrNotFound", i+1, err)
+		}
+	}
+
+	_, err = authenticate(dao, attacker, "limits@example.com", "password123")
+	if !errors.Is(err, errTooManyAttempts) {
+		t.Errorf("right password after the limit = %v, want errTooManyAttempts", err)
+	}
+	user, err := authenticate(dao, owner, "Limits@example.com", "password123")
+	if err != nil || user.Email != "limits@example.com" {
+		t.Errorf("right password from another client = %v, %v; want the user", user, err)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+
+	. "memories/model"
+)
+
+// personName joins the parts of a person's name that are known
+func personName(person Person) string {
+	return strings.Join(strings.Fields(person.First+" "+person.Middle+" "+person.Last), " ")
+}
+
+// nextAnniversary is the first occurrence of month/day on or after today. February 29 falls
+// on February 28 in other years.
+func nextAnniversary(month, day int, today time.Time) time.Time {
+	for year := today.Year(); ; year++ {
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+		if date.Month() != time.Month(month) {
+			date = time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC)
+		}
+		if !date.Before(today) {
+			return date
+		}
+	}
+}
+
+// upcomingBirthdays lists the birthdays in the next days days, starting today, soonest first.
+// People without a birth month and day are skipped.
+func upcomingBirthdays(people []Person, now time.Time, days int) []UpcomingBirthday {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	birthdays := []UpcomingBirthday{}
+	for _, person := range people {
+		if person.BirthMonth == 0 || person.BirthDay == 0 {
+			continue
+		}
+		date := nextAnniversary(person.BirthMonth, person.BirthDay, today)
+		until := int(date.Sub(today).Hours() / 24)
+		if until >= days {
+			continue
+		}
+
+		birthday := UpcomingBirthday{
+			PersonID:  person.ID,
+			Name:      personName(person),
+			Date:      date.Format("2006-01-02"),
+			DaysUntil: until,
+		}
+		if person.BirthYear > 0 {
+			birthday.Turning = date.Year() - person.BirthYear
+		}
+		birthdays = append(birthdays, birthday)
+	}
+
+	slices.SortFunc(birthdays, func(a, b UpcomingBirthday) int {
+		return cmp.Or(cmp.Compare(a.DaysUntil, b.DaysUntil), cmp.Compare(a.Name, b.Name))
+	})
+	return birthdays
+}
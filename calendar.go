@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	. "memories/model"
+)
+
+// calendarEvent is an all day event that repeats every year
+type calendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Year        int
+	Month       int
+	Day         int
+}
+
+// birthdayCalendar builds an iCalendar (RFC 5545) feed of everyone's birthday and the
+// anniversaries of life events, each repeating yearly from when it first happened
+func birthdayCalendar(people []Person, events []LifeEvent, now time.Time) string {
+	var entries []calendarEvent
+	for _, person := range people {
+		if person.BirthMonth == 0 || person.BirthDay == 0 {
+			continue
+		}
+		entry := calendarEvent{
+			UID:     fmt.Sprintf("person-%d-birthday@memories", person.ID),
+			Summary: fmt.Sprintf("🎂 %s's birthday", personName(person)),
+			Year:    person.BirthYear,
+			Month:   person.BirthMonth,
+			Day:     person.BirthDay,
+		}
+		if person.BirthYear > 0 {
+			entry.Description = fmt.Sprintf("Born in %d", person.BirthYear)
+		}
+		entries = append(entries, entry)
+	}
+	for _, event := range events {
+		if event.Month == 0 || event.Day == 0 {
+			continue
+		}
+		entries = append(entries, calendarEvent{
+			UID:         fmt.Sprintf("life-event-%d@memories", event.ID),
+			Summary:     fmt.Sprintf("🎉 %s anniversary", event.Title),
+			Description: strings.TrimSpace(fmt.Sprintf("Since %d. %s", event.Year, event.Notes)),
+			Year:        event.Year,
+			Month:       event.Month,
+			Day:         event.Day,
+		})
+	}
+
+	var b strings.Builder
+	writeContentLine(&b, "BEGIN:VCALENDAR")
+	writeContentLine(&b, "VERSION:2.0")
+	writeContentLine(&b, "PRODID:-//memories//birthdays//EN")
+	writeContentLine(&b, "CALSCALE:GREGORIAN")
+	writeContentLine(&b, "X-WR-CALNAME:Birthdays & Anniversaries")
+	stamp := now.UTC().Format("20060102T150405Z")
+	for _, entry := range entries {
+		// Birthdays without a known year still need a start; 2000 is a leap year so
+		// February 29 stays valid
+		year := entry.Year
+		if year == 0 {
+			year = 2000
+		}
+		rule := "FREQ=YEARLY"
+		if entry.Month == 2 && entry.Day == 29 {
+			rule += ";BYMONTH=2;BYMONTHDAY=-1"
+		}
+
+		writeContentLine(&b, "BEGIN:VEVENT")
+		writeContentLine(&b, "UID:"+entry.UID)
+		writeContentLine(&b, "DTSTAMP:"+stamp)
+		writeContentLine(&b, fmt.Sprintf("DTSTART;VALUE=DATE:%04d%02d%02d", year, entry.Month, entry.Day))
+		writeContentLine(&b, "RRULE:"+rule)
+		writeContentLine(&b, "SUMMARY:"+escapeContentText(entry.Summary))
+		if entry.Description != "" {
+			writeContentLine(&b, "DESCRIPTION:"+escapeContentText(entry.Description))
+		}
+		writeContentLine(&b, "TRANSP:TRANSPARENT")
+		writeContentLine(&b, "END:VEVENT")
+	}
+	writeContentLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// escapeContentText escapes the characters that are special in iCalendar and vCard text values
+func escapeContentText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(text)
+}
+
+// writeContentLine ends an iCalendar or vCard content line with CRLF, folding it so no line
+// is over 75 octets without splitting a UTF-8 character
+func writeContentLine(b *strings.Builder, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts toward the limit
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	. "memories/model"
+)
+
+// overduePeople lists the people with a contact cadence we haven't been in touch with within
+// it, those we've never been in touch with first and then the most overdue
+func overduePeople(people []Person, now time.Time) []OverduePerson {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	overdue := []OverduePerson{}
+	for _, person := range people {
+		if person.ContactEveryDays <= 0 {
+			continue
+		}
+		if person.LastContacted == "" {
+			overdue = append(overdue, OverduePerson{Person: person})
+			continue
+		}
+
+		last, err := time.Parse("2006-01-02", person.LastContacted)
+		if err != nil {
+			continue
+		}
+		since := int(today.Sub(last).Hours() / 24)
+		if since <= person.ContactEveryDays {
+			continue
+		}
+		overdue = append(overdue, OverduePerson{Person: person, DaysSinceContact: &since, DaysOverdue: since - person.ContactEveryDays})
+	}
+
+	slices.SortStableFunc(overdue, func(a, b OverduePerson) int {
+		if (a.DaysSinceContact == nil) != (b.DaysSinceContact == nil) {
+			if a.DaysSinceContact == nil {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(b.DaysOverdue, a.DaysOverdue)
+	})
+	return overdue
+}
@@ -0,0 +1,727 @@
+package daos
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"memories/exif"
+	. "memories/model"
+)
+
+// comparableTables maps each comparison category to the table holding its items
+var comparableTables = map[string]string{
+	"movies": "watched_movies",
+	"books":  "books",
+}
+
+// renameComparedItem moves an item's Elo score and the comparisons it was part of to its new title
+func renameComparedItem(tx *sql.Tx, user, category, title, newTitle string, placeholder func(n int) string) error {
+	if title == newTitle {
+		return nil
+	}
+
+	// Anything still filed under the new title belonged to an item that's gone
+	err := forgetComparedItem(tx, user, category, newTitle, placeholder)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(fmt.Sprintf("UPDATE elo_scores SET title = %s WHERE user_uuid = %s AND category = %s AND title = %s",
+		placeholder(1), placeholder(2), placeholder(3), placeholder(4)), newTitle, user, category, title)
+	if err != nil {
+		return fmt.Errorf("failed to rename score: %w", err)
+	}
+	for _, column := range []string{"winner", "loser"} {
+		_, err = tx.Exec(fmt.Sprintf("UPDATE comparisons SET %s = %s WHERE user_uuid = %s AND category = %s AND %s = %s",
+			column, placeholder(1), placeholder(2), placeholder(3), column, placeholder(4)), newTitle, user, category, title)
+		if err != nil {
+			return fmt.Errorf("failed to rename comparisons: %w", err)
+		}
+	}
+	return nil
+}
+
+// forgetComparedItem removes an item's Elo score and the comparisons it was part of, so
+// nothing saved later under the same title inherits them
+func forgetComparedItem(tx *sql.Tx, user, category, title string, placeholder func(n int) string) error {
+	_, err := tx.Exec(fmt.Sprintf("DELETE FROM elo_scores WHERE user_uuid = %s AND category = %s AND title = %s",
+		placeholder(1), placeholder(2), placeholder(3)), user, category, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete score: %w", err)
+	}
+	_, err = tx.Exec(fmt.Sprintf("DELETE FROM comparisons WHERE user_uuid = %s AND category = %s AND (winner = %s OR loser = %s)",
+		placeholder(1), placeholder(2), placeholder(3), placeholder(4)), user, category, title, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete comparisons: %w", err)
+	}
+	return nil
+}
+
+// nullIfEmpty stores empty strings as NULL, which keeps DATE columns valid in Postgres
+func nullIfEmpty(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// nullIfZero stores unknown parts of a partial date as NULL
+func nullIfZero(value int) any {
+	if value == 0 {
+		return nil
+	}
+	return value
+}
+
+// expectAffected turns an UPDATE or DELETE that touched no rows into ErrNotFound
+func expectAffected(result sql.Result) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// queryStrings runs a query returning a single text column and collects the values
+func queryStrings(tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	return values, rows.Err()
+}
+
+// sameStrings reports whether a and b hold the same values, ignoring order
+func sameStrings(a, b []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(a)), slices.Sorted(slices.Values(b)))
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// queryPeopleLinks reads (row id, person id) pairs from a join table into a map keyed by row id
+func queryPeopleLinks(db *sql.DB, query string, args ...any) (map[int][]int, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query people links: %w", err)
+	}
+	defer rows.Close()
+
+	links := make(map[int][]int)
+	for rows.Next() {
+		var id, personID int
+		if err := rows.Scan(&id, &personID); err != nil {
+			return nil, fmt.Errorf("failed to scan people link: %w", err)
+		}
+		links[id] = append(links[id], personID)
+	}
+
+	return links, rows.Err()
+}
+
+// scanTheaterMovie reads a theater movie row along with its optional watched_movies match
+func scanTheaterMovie(row rowScanner) (TheaterMovie, error) {
+	var movie TheaterMovie
+	var watchedTitle sql.NullString
+	var watched Movie
+	err := row.Scan(&movie.ID, &movie.Title, &movie.Date, &movie.People, &movie.Notes,
+		&watchedTitle, &watched.Tier, &watched.Position, &watched.Rating, &watched.Notes, &watched.WatchedDate)
+	if err != nil {
+		return movie, err
+	}
+	if watchedTitle.Valid {
+		watched.Title = watchedTitle.String
+		movie.WatchedMovie = &watched
+	}
+	return movie, nil
+}
+
+// scanTrip reads a travel row, returning the legacy free text places alongside the trip
+func scanTrip(row rowScanner) (Trip, string, error) {
+	var trip Trip
+	var places string
+	err := row.Scan(&trip.ID, &trip.Title, &trip.StartDate, &trip.EndDate, &places, &trip.People, &trip.Notes)
+	return trip, places, err
+}
+
+// scanLifeEvent reads a life_events row
+func scanLifeEvent(row rowScanner) (LifeEvent, error) {
+	var event LifeEvent
+	err := row.Scan(&event.ID, &event.Title, &event.Year, &event.Month, &event.Day, &event.Notes)
+	return event, err
+}
+
+// scanMemory reads a random_memories row
+func scanMemory(row rowScanner) (Memory, error) {
+	var memory Memory
+	err := row.Scan(&memory.ID, &memory.Date, &memory.Notes, &memory.People)
+	return memory, err
+}
+
+// scanVideoGame reads a video_games row
+func scanVideoGame(row rowScanner) (VideoGame, error) {
+	var game VideoGame
+	err := row.Scan(&game.ID, &game.Title, &game.Platform, &game.Status, &game.HoursPlayed, &game.Rating, &game.Multiplayer, &game.Notes)
+	return game, err
+}
+
+// scanBook reads a books row
+func scanBook(row rowScanner) (Book, error) {
+	var book Book
+	err := row.Scan(&book.Title, &book.Rating, &book.Pages, &book.Author, &book.Series, &book.SeriesSequence, &book.Finished, &book.DateFinished, &book.Owned, &book.CurrentPage)
+	return book, err
+}
+
+// scanTVShow reads a tv_shows row
+func scanTVShow(row rowScanner) (TVShow, error) {
+	var show TVShow
+	err := row.Scan(&show.Title, &show.Notes, &show.SeasonsWatched, &show.ChildhoodShow)
+	return show, err
+}
+
+// scanFoodPlace reads a food_places row followed by its visit count, average dish rating
+// and last visit date
+func scanFoodPlace(row rowScanner) (FoodPlace, error) {
+	var place FoodPlace
+	err := row.Scan(&place.Name, &place.Location, &place.Notes, &place.Type, &place.Category, &place.Latitude, &place.Longitude,
+		&place.Wishlist, &place.Visits, &place.AverageRating, &place.LastVisit)
+	return place, err
+}
+
+// sqlExecutor is satisfied by both *sql.DB and *sql.Tx
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// parseLocation splits free text like "denver, co, usa" into city, region and country,
+// fixing the case of parts typed in all lower or all upper case. Short regions and
+// countries are taken to be abbreviations.
+func parseLocation(text string) (city, region, country string) {
+	var parts []string
+	for _, part := range strings.Split(text, ",") {
+		if part = strings.Join(strings.Fields(part), " "); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return "", "", ""
+	}
+	// Anything beyond three parts belongs to the city, e.g. a neighbourhood
+	for len(parts) > 3 {
+		parts = append([]string{parts[0] + ", " + parts[1]}, parts[2:]...)
+	}
+
+	city = normalizeLocationPart(parts[0], false)
+	if len(parts) > 1 {
+		region = normalizeLocationPart(parts[1], true)
+	}
+	if len(parts) > 2 {
+		country = normalizeLocationPart(parts[2], true)
+	}
+	return city, region, country
+}
+
+func normalizeLocationPart(part string, abbreviation bool) string {
+	if part != strings.ToLower(part) && part != strings.ToUpper(part) {
+		return part
+	}
+	if abbreviation && len(part) <= 3 {
+		return strings.ToUpper(part)
+	}
+
+	words := strings.Fields(strings.ToLower(part))
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(first)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+// locationName joins the known parts of a location for display
+func locationName(city, region, country string) string {
+	var parts []string
+	for _, part := range []string{city, region, country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// findOrCreateLocation returns the id and display name of the location described by text,
+// adding it to the locations table the first time it is seen. Empty text has no location.
+// placeholder renders the nth query argument in the DAO's SQL dialect.
+func findOrCreateLocation(db sqlExecutor, text string, placeholder func(n int) string) (any, string, error) {
+	city, region, country := parseLocation(text)
+	if city == "" {
+		return nil, "", nil
+	}
+	key := strings.ToLower(locationName(city, region, country))
+
+	insertQuery := fmt.Sprintf("INSERT INTO locations (city, region, country, name_key) VALUES (%s, %s, %s, %s) ON CONFLICT (name_key) DO NOTHING",
+		placeholder(1), placeholder(2), placeholder(3), placeholder(4))
+	_, err := db.Exec(insertQuery, city, region, country, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to insert location: %w", err)
+	}
+
+	// The stored spelling wins so every place in a city shows it the same way
+	var id int
+	err = db.QueryRow("SELECT id, city, region, country FROM locations WHERE name_key = "+placeholder(1), key).Scan(&id, &city, &region, &country)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get location: %w", err)
+	}
+	return id, locationName(city, region, country), nil
+}
+
+// locationPattern turns a search into a LIKE pattern matching location keys that start with it
+func locationPattern(search string) string {
+	search = strings.ToLower(strings.Join(strings.Fields(search), " "))
+	search = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(search)
+	return search + "%"
+}
+
+// migrateFoodLocations links food places saved before the locations table existed to a
+// normalized location, rewriting their location text to match
+func migrateFoodLocations(db *sql.DB, placeholder func(n int) string) error {
+	rows, err := db.Query("SELECT name, location FROM food_places WHERE location_id IS NULL AND location IS NOT NULL AND location <> ''")
+	if err != nil {
+		return fmt.Errorf("failed to query food places: %w", err)
+	}
+
+	// Collect everything first so the updates don't run while the read is still open
+	found := make(map[string]string)
+	for rows.Next() {
+		var name, location string
+		if err := rows.Scan(&name, &location); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan food place: %w", err)
+		}
+		found[name] = location
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read food places: %w", err)
+	}
+
+	updateQuery := fmt.Sprintf("UPDATE food_places SET location = %s, location_id = %s WHERE name = %s", placeholder(1), placeholder(2), placeholder(3))
+	for name, location := range found {
+		id, display, err := findOrCreateLocation(db, location, placeholder)
+		if err != nil {
+			return err
+		}
+		if id == nil {
+			continue
+		}
+		_, err = db.Exec(updateQuery, display, id, name)
+		if err != nil {
+			return fmt.Errorf("failed to update food place %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// scanLocation reads a locations row followed by the number of food places there
+func scanLocation(row rowScanner) (Location, error) {
+	var location Location
+	err := row.Scan(&location.ID, &location.City, &location.Region, &location.Country, &location.Count)
+	location.Name = locationName(location.City, location.Region, location.Country)
+	return location, err
+}
+
+// queryFoodDishes reads (visit id, dish name, rating) rows into a map keyed by visit id
+func queryFoodDishes(db *sql.DB, query string, args ...any) (map[int][]Dish, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dishes: %w", err)
+	}
+	defer rows.Close()
+
+	dishes := make(map[int][]Dish)
+	for rows.Next() {
+		var visitID int
+		var dish Dish
+		if err := rows.Scan(&visitID, &dish.Name, &dish.Rating); err != nil {
+			return nil, fmt.Errorf("failed to scan dish: %w", err)
+		}
+		dishes[visitID] = append(dishes[visitID], dish)
+	}
+
+	return dishes, rows.Err()
+}
+
+// ownedTables have a user_uuid column naming the user each row belongs to. Tables come before
+// the ones that refer to them by name, so foreign keys hold while rows are claimed in order.
+var ownedTables = []string{"concerts", "watched_movies", "theater_movies", "travel", "life_events", "random_memories", "video_games",
+	"books", "reading_goals", "food_places", "people", "tv_shows", "journal_entries", "files", "comparisons", "elo_scores",
+	"reading_sessions", "food_visits", "tv_seasons", "tv_episodes", "concert_people"}
+
+// claimUnownedRows gives every row saved before there were user accounts to the user,
+// returning how many rows there were
+func claimUnownedRows(db *sql.DB, uuid string, placeholder func(n int) string) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	claimed := 0
+	for _, table := range ownedTables {
+		result, err := tx.Exec(fmt.Sprintf("UPDATE %s SET user_uuid = %s WHERE user_uuid IS NULL", table, placeholder(1)), uuid)
+		if err != nil {
+			return 0, fmt.Errorf("failed to claim %s: %w", table, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to count claimed %s: %w", table, err)
+		}
+		claimed += int(n)
+	}
+	return claimed, tx.Commit()
+}
+
+// personTables hold the rows that belong to a person, which are removed along with them
+var personTables = []string{"concert_people", "travel_people", "theater_movie_people", "memory_people", "food_visit_people", "gift_ideas", "interactions"}
+
+// scanUser reads a users row
+func scanUser(row rowScanner) (User, error) {
+	var user User
+	err := row.Scan(&user.UUID, &user.Email, &user.Created, &user.PasswordHash, &user.Salt)
+	return user, err
+}
+
+// scanInteraction reads an interactions row
+func scanInteraction(row rowScanner) (Interaction, error) {
+	var interaction Interaction
+	err := row.Scan(&interaction.ID, &interaction.PersonID, &interaction.Date, &interaction.Kind, &interaction.Notes)
+	return interaction, err
+}
+
+// scanPerson reads a people row followed by the date of the latest interaction
+func scanPerson(row rowScanner) (Person, error) {
+	var person Person
+	err := row.Scan(&person.ID, &person.First, &person.Middle, &person.Last, &person.Address,
+		&person.BirthDay, &person.BirthMonth, &person.BirthYear, &person.Email, &person.Category, &person.Notes,
+		&person.ContactEveryDays, &person.LastContacted)
+	return person, err
+}
+
+// queryGiftIdeas reads (person id, idea, status, occasion, price) rows into a map keyed by person id
+func queryGiftIdeas(db *sql.DB, query string, args ...any) (map[int][]GiftIdea, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query gift ideas: %w", err)
+	}
+	defer rows.Close()
+
+	gifts := make(map[int][]GiftIdea)
+	for rows.Next() {
+		var personID int
+		var gift GiftIdea
+		if err := rows.Scan(&personID, &gift.Idea, &gift.Status, &gift.Occasion, &gift.Price); err != nil {
+			return nil, fmt.Errorf("failed to scan gift idea: %w", err)
+		}
+		gifts[personID] = append(gifts[personID], gift)
+	}
+
+	return gifts, rows.Err()
+}
+
+// migrateGiftIdeas moves ideas out of the old people.gift_ideas column into the gift_ideas
+// table, clearing the column as it goes. legacyQuery selects each person's id and their
+// old ideas separated by newlines.
+func migrateGiftIdeas(db *sql.DB, legacyQuery string, placeholder func(n int) string) error {
+	rows, err := db.Query(legacyQuery)
+	if err != nil {
+		return fmt.Errorf("failed to query old gift ideas: %w", err)
+	}
+
+	// Collect everything first so the updates don't run while the read is still open
+	found := make(map[int][]string)
+	for rows.Next() {
+		var id int
+		var ideas string
+		if err := rows.Scan(&id, &ideas); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan old gift ideas: %w", err)
+		}
+		for _, idea := range strings.Split(ideas, "\n") {
+			if idea = strings.TrimSpace(idea); idea != "" {
+				found[id] = append(found[id], idea)
+			}
+		}
+		if found[id] == nil {
+			found[id] = []string{}
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read old gift ideas: %w", err)
+	}
+
+	insertQuery := fmt.Sprintf("INSERT INTO gift_ideas (person_id, position, idea, status) VALUES (%s, %s, %s, %s)",
+		placeholder(1), placeholder(2), placeholder(3), placeholder(4))
+	clearQuery := "UPDATE people SET gift_ideas = NULL WHERE id = " + placeholder(1)
+	for id, ideas := range found {
+		if err := moveGiftIdeas(db, id, ideas, insertQuery, clearQuery); err != nil {
+			return fmt.Errorf("failed to migrate gift ideas of person %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// moveGiftIdeas saves one person's old gift ideas and clears the old column in a transaction
+func moveGiftIdeas(db *sql.DB, id int, ideas []string, insertQuery, clearQuery string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for i, idea := range ideas {
+		_, err = tx.Exec(insertQuery, id, i, idea, GiftStatuses[0])
+		if err != nil {
+			return fmt.Errorf("failed to insert gift idea: %w", err)
+		}
+	}
+	_, err = tx.Exec(clearQuery, id)
+	if err != nil {
+		return fmt.Errorf("failed to clear old gift ideas: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// queryTripPlaces reads trip stops into a map keyed by trip id, each list in visiting order
+func queryTripPlaces(db *sql.DB, query string, args ...any) (map[int][]TripPlace, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trip places: %w", err)
+	}
+	defer rows.Close()
+
+	places := make(map[int][]TripPlace)
+	for rows.Next() {
+		var tripID int
+		var place TripPlace
+		var latitude, longitude sql.NullFloat64
+		if err := rows.Scan(&tripID, &place.Name, &latitude, &longitude); err != nil {
+			return nil, fmt.Errorf("failed to scan trip place: %w", err)
+		}
+		if latitude.Valid && longitude.Valid {
+			place.Latitude, place.Longitude = &latitude.Float64, &longitude.Float64
+		}
+		places[tripID] = append(places[tripID], place)
+	}
+
+	return places, rows.Err()
+}
+
+// queryTVSeasons reads (show title, season, episode number, episode title, watched date) rows
+// into a map keyed by show title, each show's seasons and episodes in order. Seasons without
+// episodes come back with NULL episode columns.
+func queryTVSeasons(db *sql.DB, query string, args ...any) (map[string][]TVSeason, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query TV seasons: %w", err)
+	}
+	defer rows.Close()
+
+	seasons := make(map[string][]TVSeason)
+	for rows.Next() {
+		var title string
+		var season int
+		var number sql.NullInt64
+		var episodeTitle, watched sql.NullString
+		if err := rows.Scan(&title, &season, &number, &episodeTitle, &watched); err != nil {
+			return nil, fmt.Errorf("failed to scan TV season: %w", err)
+		}
+
+		showSeasons := seasons[title]
+		if len(showSeasons) == 0 || showSeasons[len(showSeasons)-1].Number != season {
+			showSeasons = append(showSeasons, TVSeason{Number: season, Episodes: []TVEpisode{}})
+		}
+		if number.Valid {
+			last := &showSeasons[len(showSeasons)-1]
+			last.Episodes = append(last.Episodes, TVEpisode{
+				Season:      season,
+				Number:      int(number.Int64),
+				Title:       episodeTitle.String,
+				WatchedDate: watched.String,
+			})
+		}
+		seasons[title] = showSeasons
+	}
+
+	return seasons, rows.Err()
+}
+
+// nextTVEpisode finds the episode after the furthest one watched, so episodes skipped on the
+// way aren't suggested again. Returns the first episode if nothing has been watched and nil
+// once the last episode has been.
+func nextTVEpisode(seasons []TVSeason) *TVEpisode {
+	var episodes []TVEpisode
+	for _, season := range seasons {
+		episodes = append(episodes, season.Episodes...)
+	}
+
+	next := 0
+	for i, episode := range episodes {
+		if episode.WatchedDate != "" {
+			next = i + 1
+		}
+	}
+	if next >= len(episodes) {
+		return nil
+	}
+	return &episodes[next]
+}
+
+// legacyTripPlaces splits the free text travel.places column of trips logged before
+// stops were stored in trip_places
+func legacyTripPlaces(text string) []TripPlace {
+	var places []TripPlace
+	for _, name := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' || r == '\n' }) {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			places = append(places, TripPlace{Name: name})
+		}
+	}
+	return places
+}
+
+// tripPlaceNames joins a trip's stops for the free text travel.places column
+func tripPlaceNames(places []TripPlace) string {
+	names := make([]string, len(places))
+	for i, place := range places {
+		names[i] = place.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// photoMetadataVersion is bumped whenever photoMetadata starts reading a new field,
+// so photos read by an older version are read again at startup
+const photoMetadataVersion = 2
+
+// photoMetadata reads what it can from a photo's EXIF block; photos without one get empty metadata
+func photoMetadata(bytes []byte) exif.Metadata {
+	metadata, err := exif.Read(bytes)
+	if err != nil {
+		return exif.Metadata{}
+	}
+	return *metadata
+}
+
+// photoTaken formats when a photo was taken for the files.taken column
+func photoTaken(metadata exif.Metadata) any {
+	if metadata.Taken == nil {
+		return nil
+	}
+	return metadata.Taken.Format("2006-01-02 15:04:05")
+}
+
+// readMissingPhotoMetadata fills in the EXIF columns of photos uploaded before they existed.
+// updateQuery takes latitude, longitude, taken, metadata_version and id, in that order.
+func readMissingPhotoMetadata(db *sql.DB, updateQuery string) error {
+	query := fmt.Sprintf("SELECT id, bytes FROM files WHERE metadata_version IS NULL OR metadata_version < %d", photoMetadataVersion)
+	rows, err := db.Query(query)
+	if err != nil {
+		return fmt.Errorf("failed to query photos: %w", err)
+	}
+
+	// Collect everything first so the updates don't run while the read is still open
+	found := make(map[int]exif.Metadata)
+	for rows.Next() {
+		var id int
+		var bytes []byte
+		if err := rows.Scan(&id, &bytes); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan photo: %w", err)
+		}
+		found[id] = photoMetadata(bytes)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read photos: %w", err)
+	}
+
+	for id, metadata := range found {
+		_, err = db.Exec(updateQuery, metadata.Latitude, metadata.Longitude, photoTaken(metadata), photoMetadataVersion, id)
+		if err != nil {
+			return fmt.Errorf("failed to update photo %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// timelineSource is one category's branch of the timeline UNION. Query selects type, date,
+// title, summary and link and ends in a WHERE clause; DateColumn is the full date the rows
+// are filtered on.
+type timelineSource struct {
+	Query      string
+	DateColumn string
+}
+
+// buildTimelineQuery unions the sources selected by query, filtering each branch on its own
+// date column and the user's rows so they are narrowed before they are merged and sorted.
+// placeholder renders the nth query argument in the DAO's SQL dialect.
+func buildTimelineQuery(sources map[string]timelineSource, query TimelineQuery, user string, placeholder func(n int) string) (string, []any, error) {
+	// The upper bound is inclusive, so compare against the start of the next day
+	var before string
+	if query.To != "" {
+		to, err := time.Parse("2006-01-02", query.To)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid timeline end date: %w", err)
+		}
+		before = to.AddDate(0, 0, 1).Format("2006-01-02")
+	}
+
+	var branches []string
+	var args []any
+	for _, typ := range TimelineTypes {
+		if len(query.Types) > 0 && !slices.Contains(query.Types, typ) {
+			continue
+		}
+		source := sources[typ]
+		args = append(args, user)
+		branch := source.Query + " AND user_uuid = " + placeholder(len(args))
+		if query.From != "" {
+			args = append(args, query.From)
+			branch += " AND " + source.DateColumn + " >= " + placeholder(len(args))
+		}
+		if before != "" {
+			args = append(args, before)
+			branch += " AND " + source.DateColumn + " < " + placeholder(len(args))
+		}
+		branches = append(branches, branch)
+	}
+
+	args = append(args, query.Limit)
+	limit := placeholder(len(args))
+	args = append(args, query.Offset)
+	offset := placeholder(len(args))
+
+	return strings.Join(branches, "\nUNION ALL\n") + "\nORDER BY 2 DESC, 1, 3 LIMIT " + limit + " OFFSET " + offset, args, nil
+}
@@ -0,0 +1,286 @@
+package daos
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	. "memories/model"
+)
+
+// Separators used when people were typed into a free text column ("Bob, Sue and Tim")
+var peopleSeparators = regexp.MustCompile(`(?i)\s*(?:,|;|/|&|\+|\n|\band\b|\bwith\b)\s*`)
+
+// splitPeopleNames breaks a free text people column into individual names
+func splitPeopleNames(text string) []string {
+	var names []string
+	for _, name := range peopleSeparators.Split(text, -1) {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// normalizeName lowercases a name and strips everything but letters, digits and single spaces
+func normalizeName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && b.Len() > 0 {
+				b.WriteRune(' ')
+			}
+			space = false
+			b.WriteRune(r)
+		case unicode.IsSpace(r) || r == '-' || r == '.':
+			space = true
+		}
+	}
+	return b.String()
+}
+
+// levenshtein returns the edit distance between two strings
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// personNameKeys returns the normalized spellings a person may be referred to by
+func personNameKeys(person Person) []string {
+	first := normalizeName(person.First)
+	last := normalizeName(person.Last)
+	middle := normalizeName(person.Middle)
+
+	var keys []string
+	if first != "" && last != "" {
+		keys = append(keys, first+" "+last)
+		if middle != "" {
+			keys = append(keys, first+" "+middle+" "+last)
+		}
+		keys = append(keys, first+" "+string([]rune(last)[:1]))
+	}
+	if first != "" {
+		keys = append(keys, first)
+	}
+	return keys
+}
+
+// matchPerson finds the person a free text name most likely refers to.
+// Exact matches win, otherwise the closest spelling within a small edit distance is used.
+// A name that matches more than one person equally well is treated as unmatched.
+func matchPerson(name string, people []Person) (int, bool) {
+	target := normalizeName(name)
+	if target == "" {
+		return 0, false
+	}
+
+	bestID, bestDistance, tied := 0, -1, false
+	for _, person := range people {
+		distance := -1
+		for _, key := range personNameKeys(person) {
+			d := levenshtein(target, key)
+			if distance == -1 || d < distance {
+				distance = d
+			}
+		}
+		if distance == -1 {
+			continue
+		}
+
+		switch {
+		case bestDistance == -1 || distance < bestDistance:
+			bestID, bestDistance, tied = person.ID, distance, false
+		case distance == bestDistance && person.ID != bestID:
+			tied = true
+		}
+	}
+
+	// Allow roughly one typo per four characters
+	maxDistance := len([]rune(target)) / 4
+	if bestDistance == -1 || bestDistance > maxDistance || tied {
+		return 0, false
+	}
+	return bestID, true
+}
+
+// queryRefTexts reads (reference, text) pairs into memory so the caller can write
+// to the same transaction afterwards
+func queryRefTexts(tx *sql.Tx, query string, args ...any) ([]string, []string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var refs, texts []string
+	for rows.Next() {
+		var ref, text string
+		if err := rows.Scan(&ref, &text); err != nil {
+			return nil, nil, err
+		}
+		refs = append(refs, ref)
+		texts = append(texts, text)
+	}
+
+	return refs, texts, rows.Err()
+}
+
+// peopleLinkSource is a free text people column and the join table it's linked through.
+// query selects (reference, text) pairs and is formatted with a condition on the owner,
+// and insert links a reference to a person id, doing nothing if they're already linked.
+type peopleLinkSource struct {
+	kind   string
+	query  string
+	insert string
+}
+
+// linkPeople links the names in each source to the people they match. owner is the condition
+// on user_uuid the source queries are formatted with and args are its arguments.
+func linkPeople(tx *sql.Tx, people []Person, sources []peopleLinkSource, owner string, args ...any) (*PeopleLinkReport, error) {
+	report := &PeopleLinkReport{Unmatched: []UnmatchedPerson{}}
+	for _, source := range sources {
+		refs, texts, err := queryRefTexts(tx, fmt.Sprintf(source.query, owner), args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s people: %w", source.kind, err)
+		}
+
+		for i, ref := range refs {
+			for _, name := range splitPeopleNames(texts[i]) {
+				personID, ok := matchPerson(name, people)
+				if !ok {
+					report.Unmatched = append(report.Unmatched, UnmatchedPerson{Type: source.kind, Ref: ref, Name: name})
+					continue
+				}
+
+				result, err := tx.Exec(source.insert, ref, personID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to link %s people: %w", source.kind, err)
+				}
+				if n, err := result.RowsAffected(); err == nil {
+					report.Linked += int(n)
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// migratePeopleLinks links the names typed into the free text people columns before the join
+// tables existed, once per database, logging the names it couldn't match. Rows are matched
+// against the people with the same owner, including rows nobody has claimed yet.
+func migratePeopleLinks(db *sql.DB, sources []peopleLinkSource, placeholder func(n int) string) error {
+	var applied int
+	err := db.QueryRow("SELECT COUNT(*) FROM migrations WHERE name = "+placeholder(1), "people_links").Scan(&applied)
+	if err != nil {
+		return fmt.Errorf("failed to query migrations: %w", err)
+	}
+	if applied > 0 {
+		return nil
+	}
+
+	owners, err := queryOwners(db)
+	if err != nil {
+		return err
+	}
+
+	ownerCondition := "COALESCE(user_uuid, '') = " + placeholder(1)
+	for _, owner := range owners {
+		if err := linkOwnerPeople(db, sources, ownerCondition, owner); err != nil {
+			return err
+		}
+	}
+
+	_, err = db.Exec(fmt.Sprintf("INSERT INTO migrations (name, applied) VALUES (%s, %s)", placeholder(1), placeholder(2)), "people_links", time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to record migration: %w", err)
+	}
+	return nil
+}
+
+// queryOwners lists everyone with rows that have free text people, "" for rows without an owner
+func queryOwners(db *sql.DB) ([]string, error) {
+	rows, err := db.Query(`SELECT COALESCE(user_uuid, '') FROM people
+UNION SELECT COALESCE(user_uuid, '') FROM concerts
+UNION SELECT COALESCE(user_uuid, '') FROM travel
+UNION SELECT COALESCE(user_uuid, '') FROM theater_movies
+UNION SELECT COALESCE(user_uuid, '') FROM random_memories`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query owners: %w", err)
+	}
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var owner string
+		if err := rows.Scan(&owner); err != nil {
+			return nil, fmt.Errorf("failed to scan owner: %w", err)
+		}
+		owners = append(owners, owner)
+	}
+	return owners, rows.Err()
+}
+
+// linkOwnerPeople links one owner's rows to their people in a transaction
+func linkOwnerPeople(db *sql.DB, sources []peopleLinkSource, ownerCondition, owner string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT id, COALESCE(first, ''), COALESCE(middle, ''), COALESCE(last, '') FROM people WHERE "+ownerCondition, owner)
+	if err != nil {
+		return fmt.Errorf("failed to query people: %w", err)
+	}
+	var people []Person
+	for rows.Next() {
+		var person Person
+		if err := rows.Scan(&person.ID, &person.First, &person.Middle, &person.Last); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan person: %w", err)
+		}
+		people = append(people, person)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read people: %w", err)
+	}
+
+	report, err := linkPeople(tx, people, sources, ownerCondition, owner)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit people links: %w", err)
+	}
+
+	log.Printf("Linked %d people from free text, %d names unmatched", report.Linked, len(report.Unmatched))
+	for _, unmatched := range report.Unmatched {
+		log.Printf("No person matches %q on %s %s", unmatched.Name, unmatched.Type, unmatched.Ref)
+	}
+	return nil
+}
@@ -1,28 +1,34 @@
 package daos
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
 
 	. "memories/model"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
-// SQLiteDAO implementation
+// SQLiteDAO implementation. Content is read and written for the user set by ForUser.
 type SQLiteDAO struct {
-	db *sql.DB
+	db   *sql.DB
+	user string
 }
 
 // Create the SQLite DB connection and create the tables if they don't already exist
 func InitSQLiteDB(path string) *sql.DB {
 	createTablesQuery := `CREATE TABLE IF NOT EXISTS video_games (
-    id INT,
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
     title VARCHAR(255),
     notes TEXT,
-    multiplayer BOOLEAN,
-    PRIMARY KEY (id)
+    multiplayer BOOLEAN
 );
 CREATE TABLE IF NOT EXISTS users (
     uuid CHAR(36),
@@ -32,13 +38,21 @@ CREATE TABLE IF NOT EXISTS users (
     created TIMESTAMP,
     PRIMARY KEY (uuid)
 );
+CREATE UNIQUE INDEX IF NOT EXISTS users_email ON users (email);
+CREATE TABLE IF NOT EXISTS sessions (
+    token_hash CHAR(64),
+    user_uuid CHAR(36) NOT NULL,
+    created TIMESTAMP,
+    expires TIMESTAMP NOT NULL,
+    PRIMARY KEY (token_hash),
+    FOREIGN KEY (user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
 CREATE TABLE IF NOT EXISTS theater_movies (
-    id INT,
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
     title VARCHAR(255),
     date DATE,
     people_went_with TEXT,
-    notes TEXT,
-    PRIMARY KEY (id)
+    notes TEXT
 );
 CREATE TABLE IF NOT EXISTS watched_movies (
     title VARCHAR(255),
@@ -53,8 +67,7 @@ CREATE TABLE IF NOT EXISTS travel (
     people_went_with TEXT,
     notes TEXT,
     dates DATE,
-    id INT,
-    PRIMARY KEY (id)
+    id INTEGER PRIMARY KEY AUTOINCREMENT
 );
 CREATE TABLE IF NOT EXISTS tv_shows (
     title VARCHAR(255),
@@ -85,13 +98,12 @@ CREATE TABLE IF NOT EXISTS food_places (
     PRIMARY KEY (name)
 );
 CREATE TABLE IF NOT EXISTS life_events (
-    id INT,
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
     title VARCHAR(255),
     month INT,
     day INT,
     year INT,
-    notes TEXT,
-    PRIMARY KEY (id)
+    notes TEXT
 );
 CREATE TABLE IF NOT EXISTS concerts (
     date DATE,
@@ -101,7 +113,7 @@ CREATE TABLE IF NOT EXISTS concerts (
     PRIMARY KEY (date)
 );
 CREATE TABLE IF NOT EXISTS people (
-    id INT,
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
     first VARCHAR(255),
     middle VARCHAR(255),
     last VARCHAR(255),
@@ -112,15 +124,13 @@ CREATE TABLE IF NOT EXISTS people (
     gift_ideas TEXT[],
     email VARCHAR(255),
     category VARCHAR(50),
-    notes TEXT,
-    PRIMARY KEY (id)
+    notes TEXT
 );
 CREATE TABLE IF NOT EXISTS random_memories (
-    id INT,
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
     date DATE,
     notes TEXT,
-    involved_people TEXT,
-    PRIMARY KEY (id)
+    involved_people TEXT
 );
 CREATE TABLE IF NOT EXISTS journal_entries (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -135,9 +145,153 @@ CREATE TABLE IF NOT EXISTS files (
 	bytes BLOB NOT NULL,
 	file_name VARCHAR(255) NOT NULL,
 	created DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS concert_people (
+    concert_date DATE,
+    person_id INT,
+    PRIMARY KEY (concert_date, person_id),
+    FOREIGN KEY (concert_date) REFERENCES concerts(date) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS travel_people (
+    travel_id INT,
+    person_id INT,
+    PRIMARY KEY (travel_id, person_id),
+    FOREIGN KEY (travel_id) REFERENCES travel(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS theater_movie_people (
+    theater_movie_id INT,
+    person_id INT,
+    PRIMARY KEY (theater_movie_id, person_id),
+    FOREIGN KEY (theater_movie_id) REFERENCES theater_movies(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS memory_people (
+    memory_id INT,
+    person_id INT,
+    PRIMARY KEY (memory_id, person_id),
+    FOREIGN KEY (memory_id) REFERENCES random_memories(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS comparisons (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    category VARCHAR(50) NOT NULL,
+    winner VARCHAR(255) NOT NULL,
+    loser VARCHAR(255) NOT NULL,
+    created DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+CREATE TABLE IF NOT EXISTS trip_places (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    trip_id INT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    latitude FLOAT,
+    longitude FLOAT,
+    FOREIGN KEY (trip_id) REFERENCES travel(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS reading_sessions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    book_title VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    pages_read INT NOT NULL DEFAULT 0,
+    minutes INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (book_title) REFERENCES books(title) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS reading_goals (
+    year INT,
+    target INT NOT NULL,
+    unit VARCHAR(10) NOT NULL,
+    PRIMARY KEY (year)
+);
+CREATE TABLE IF NOT EXISTS tv_seasons (
+    show_title VARCHAR(255),
+    season INT,
+    PRIMARY KEY (show_title, season),
+    FOREIGN KEY (show_title) REFERENCES tv_shows(title) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS tv_episodes (
+    show_title VARCHAR(255),
+    season INT,
+    episode INT,
+    title VARCHAR(255),
+    watched_date DATE,
+    PRIMARY KEY (show_title, season, episode),
+    FOREIGN KEY (show_title, season) REFERENCES tv_seasons(show_title, season) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS food_visits (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    place_name VARCHAR(255) NOT NULL,
+    date DATE,
+    cost FLOAT,
+    notes TEXT,
+    FOREIGN KEY (place_name) REFERENCES food_places(name) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS food_visit_dishes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    visit_id INT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    rating FLOAT,
+    FOREIGN KEY (visit_id) REFERENCES food_visits(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS food_visit_people (
+    visit_id INT,
+    person_id INT,
+    PRIMARY KEY (visit_id, person_id),
+    FOREIGN KEY (visit_id) REFERENCES food_visits(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS gift_ideas (
+    person_id INT NOT NULL,
+    position INT NOT NULL,
+    idea VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    occasion VARCHAR(255),
+    price FLOAT,
+    PRIMARY KEY (person_id, position),
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS interactions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    person_id INT NOT NULL,
+    date DATE NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    notes TEXT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS relationships (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    person_id INT NOT NULL,
+    related_id INT,
+    type VARCHAR(20) NOT NULL,
+    notes TEXT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE,
+    FOREIGN KEY (related_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS relationships_edge ON relationships (person_id, COALESCE(related_id, 0), type);
+CREATE TABLE IF NOT EXISTS locations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    city VARCHAR(255) NOT NULL,
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    country VARCHAR(255) NOT NULL DEFAULT '',
+    name_key VARCHAR(255) NOT NULL UNIQUE
+);
+CREATE TABLE IF NOT EXISTS elo_scores (
+    category VARCHAR(50),
+    title VARCHAR(255),
+    score FLOAT NOT NULL,
+    comparisons INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (category, title)
+);
+CREATE TABLE IF NOT EXISTS migrations (
+    name VARCHAR(255),
+    applied TIMESTAMP,
+    PRIMARY KEY (name)
 );`
 
-	db, err := sql.Open("sqlite3", path)
+	// SQLite only enforces foreign keys, and so their cascades, when each connection asks it to
+	db, err := sql.Open("sqlite3", path+"?_foreign_keys=on")
 	if err != nil {
 		log.Fatal(fmt.Sprintf("Could not open DB: %s", err))
 	}
@@ -151,17 +305,245 @@ CREATE TABLE IF NOT EXISTS files (
 		log.Fatalf("Could not create tables: %s", err)
 	}
 
+	err = addMissingSQLiteColumns(db)
+	if err != nil {
+		log.Fatalf("Could not migrate tables: %s", err)
+	}
+
+	err = addSQLiteOwnerKeys(db)
+	if err != nil {
+		log.Fatalf("Could not migrate keys: %s", err)
+	}
+
+	err = addSQLiteGeneratedIDs(db)
+	if err != nil {
+		log.Fatalf("Could not migrate ids: %s", err)
+	}
+
+	// Trips used to have a single date, which becomes the start date
+	_, err = db.Exec("UPDATE travel SET start_date = dates WHERE start_date IS NULL AND dates IS NOT NULL")
+	if err != nil {
+		log.Fatalf("Could not migrate travel dates: %s", err)
+	}
+
+	err = readMissingPhotoMetadata(db, "UPDATE files SET latitude = ?, longitude = ?, taken = ?, metadata_version = ? WHERE id = ?")
+	if err != nil {
+		log.Fatalf("Could not read photo metadata: %s", err)
+	}
+
+	err = migrateFoodLocations(db, func(n int) string { return "?" })
+	if err != nil {
+		log.Fatalf("Could not migrate food locations: %s", err)
+	}
+
+	// Gift ideas used to be a comma separated string in people.gift_ideas
+	err = migrateGiftIdeas(db, "SELECT id, REPLACE(gift_ideas, ',', char(10)) FROM people WHERE gift_ideas IS NOT NULL", func(n int) string { return "?" })
+	if err != nil {
+		log.Fatalf("Could not migrate gift ideas: %s", err)
+	}
+
+	err = migratePeopleLinks(db, sqlitePeopleLinkSources, func(n int) string { return "?" })
+	if err != nil {
+		log.Fatalf("Could not link people: %s", err)
+	}
+
 	return db
 }
 
-// NewSQLiteDAO creates a new SQLite DAO
+// Columns added after a table was first created. SQLite has no ADD COLUMN IF NOT EXISTS,
+// so each one is checked against the table before it is added.
+var sqliteAddedColumns = []struct {
+	table      string
+	column     string
+	definition string
+}{
+	{"watched_movies", "watched_date", "DATE"},
+	{"watched_movies", "position", "INT"},
+	{"travel", "start_date", "DATE"},
+	{"travel", "end_date", "DATE"},
+	{"food_places", "latitude", "FLOAT"},
+	{"food_places", "longitude", "FLOAT"},
+	{"files", "latitude", "FLOAT"},
+	{"files", "longitude", "FLOAT"},
+	{"files", "taken", "DATETIME"},
+	{"files", "metadata_version", "INT"},
+	{"video_games", "platform", "VARCHAR(100)"},
+	{"video_games", "status", "VARCHAR(20)"},
+	{"video_games", "hours_played", "FLOAT"},
+	{"video_games", "rating", "FLOAT"},
+	{"books", "current_page", "INT"},
+	{"food_places", "wishlist", "BOOLEAN"},
+	{"food_places", "location_id", "INT"},
+	{"people", "contact_every_days", "INT"},
+	{"concerts", "user_uuid", "CHAR(36)"},
+	{"watched_movies", "user_uuid", "CHAR(36)"},
+	{"theater_movies", "user_uuid", "CHAR(36)"},
+	{"travel", "user_uuid", "CHAR(36)"},
+	{"life_events", "user_uuid", "CHAR(36)"},
+	{"random_memories", "user_uuid", "CHAR(36)"},
+	{"video_games", "user_uuid", "CHAR(36)"},
+	{"books", "user_uuid", "CHAR(36)"},
+	{"reading_goals", "user_uuid", "CHAR(36)"},
+	{"food_places", "user_uuid", "CHAR(36)"},
+	{"people", "user_uuid", "CHAR(36)"},
+	{"tv_shows", "user_uuid", "CHAR(36)"},
+	{"journal_entries", "user_uuid", "CHAR(36)"},
+	{"files", "user_uuid", "CHAR(36)"},
+	{"comparisons", "user_uuid", "CHAR(36)"},
+	{"elo_scores", "user_uuid", "CHAR(36)"},
+	{"reading_sessions", "user_uuid", "CHAR(36)"},
+	{"food_visits", "user_uuid", "CHAR(36)"},
+	{"tv_seasons", "user_uuid", "CHAR(36)"},
+	{"tv_episodes", "user_uuid", "CHAR(36)"},
+	{"concert_people", "user_uuid", "CHAR(36)"},
+}
+
+func addMissingSQLiteColumns(db *sql.DB) error {
+	for _, added := range sqliteAddedColumns {
+		var count int
+		err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", added.table, added.column).Scan(&count)
+		if err != nil {
+			return fmt.Errorf("failed to inspect %s: %w", added.table, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		_, err = db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", added.table, added.column, added.definition))
+		if err != nil {
+			return fmt.Errorf("failed to add %s.%s: %w", added.table, added.column, err)
+		}
+	}
+	return nil
+}
+
+// Keys on a title or name that now include the owner, so different users can each have a
+// book or show with the same title. Each pair is the old text of the key and its replacement.
+var sqliteOwnerKeys = []struct {
+	table string
+	keys  []string
+}{
+	{"concerts", []string{"PRIMARY KEY (date)", "PRIMARY KEY (user_uuid, date)"}},
+	{"watched_movies", []string{"PRIMARY KEY (title)", "PRIMARY KEY (user_uuid, title)"}},
+	{"books", []string{"PRIMARY KEY (title)", "PRIMARY KEY (user_uuid, title)"}},
+	{"reading_goals", []string{"PRIMARY KEY (year)", "PRIMARY KEY (user_uuid, year)"}},
+	{"food_places", []string{"PRIMARY KEY (name)", "PRIMARY KEY (user_uuid, name)"}},
+	{"tv_shows", []string{"PRIMARY KEY (title)", "PRIMARY KEY (user_uuid, title)"}},
+	{"elo_scores", []string{"PRIMARY KEY (category, title)", "PRIMARY KEY (user_uuid, category, title)"}},
+	{"concert_people", []string{"FOREIGN KEY (concert_date) REFERENCES concerts(date)",
+		"FOREIGN KEY (user_uuid, concert_date) REFERENCES concerts(user_uuid, date)"}},
+	{"reading_sessions", []string{"FOREIGN KEY (book_title) REFERENCES books(title)",
+		"FOREIGN KEY (user_uuid, book_title) REFERENCES books(user_uuid, title)"}},
+	{"food_visits", []string{"FOREIGN KEY (place_name) REFERENCES food_places(name)",
+		"FOREIGN KEY (user_uuid, place_name) REFERENCES food_places(user_uuid, name)"}},
+	{"tv_seasons", []string{"PRIMARY KEY (show_title, season)", "PRIMARY KEY (user_uuid, show_title, season)",
+		"FOREIGN KEY (show_title) REFERENCES tv_shows(title)", "FOREIGN KEY (user_uuid, show_title) REFERENCES tv_shows(user_uuid, title)"}},
+	{"tv_episodes", []string{"PRIMARY KEY (show_title, season, episode)", "PRIMARY KEY (user_uuid, show_title, season, episode)",
+		"FOREIGN KEY (show_title, season) REFERENCES tv_seasons(show_title, season)",
+		"FOREIGN KEY (user_uuid, show_title, season) REFERENCES tv_seasons(user_uuid, show_title, season)"}},
+}
+
+// addSQLiteOwnerKeys rewrites the keys in sqliteOwnerKeys. SQLite can't alter a key, so each
+// table still using an old one is rebuilt from its stored definition with the keys replaced.
+func addSQLiteOwnerKeys(db *sql.DB) error {
+	for _, owner := range sqliteOwnerKeys {
+		err := rewriteSQLiteTable(db, owner.table, owner.keys...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Tables whose ids used to be one past the highest in use, which hands a deleted row's id to
+// the next one. AUTOINCREMENT never reuses an id, so calendar and contact UIDs stay unique.
+var sqliteGeneratedIDs = []string{"theater_movies", "travel", "life_events", "random_memories", "video_games", "people"}
+
+// addSQLiteGeneratedIDs makes the id of each table in sqliteGeneratedIDs AUTOINCREMENT. Only an
+// id declared in the column itself can be, so the old table key is dropped.
+func addSQLiteGeneratedIDs(db *sql.DB) error {
+	for _, table := range sqliteGeneratedIDs {
+		err := rewriteSQLiteTable(db, table, "\n    id INT,", "\n    id INTEGER PRIMARY KEY AUTOINCREMENT,", ",\n    PRIMARY KEY (id)", "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteSQLiteTable applies the old, new text pairs in replacements to a table's stored
+// definition, and rebuilds the table if that changed it
+func rewriteSQLiteTable(db *sql.DB, table string, replacements ...string) error {
+	var definition string
+	err := db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?", table).Scan(&definition)
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", table, err)
+	}
+	rewritten := strings.NewReplacer(replacements...).Replace(definition)
+	if rewritten == definition {
+		return nil
+	}
+
+	err = rebuildSQLiteTable(db, table, rewritten[strings.Index(rewritten, "("):])
+	if err != nil {
+		return fmt.Errorf("failed to rebuild %s: %w", table, err)
+	}
+	return nil
+}
+
+// rebuildSQLiteTable copies a table into a new one with the given column and key definitions,
+// then swaps it in under the old name
+func rebuildSQLiteTable(db *sql.DB, table, definition string) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection: %w", err)
+	}
+	defer conn.Close()
+
+	// Dropping the old table would cascade to the rows that refer to it, so foreign keys are
+	// off on this connection until the new one is in place. They can't change inside a transaction.
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys = OFF"); err != nil {
+		return fmt.Errorf("failed to turn off foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, "PRAGMA foreign_keys = ON")
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rebuilt := table + "_rebuilt"
+	statements := []string{
+		fmt.Sprintf("CREATE TABLE %s %s", rebuilt, definition),
+		fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", rebuilt, table),
+		fmt.Sprintf("DROP TABLE %s", table),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", rebuilt, table),
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// NewSQLiteDAO creates a new SQLite DAO. Use ForUser to get one that can read and write content.
 func NewSQLiteDAO(db *sql.DB) *SQLiteDAO {
 	return &SQLiteDAO{db: db}
 }
 
+// ForUser returns a DAO over the same database that only reads and writes the rows owned by
+// the user with the given UUID
+func (dao *SQLiteDAO) ForUser(uuid string) LifeJournalDAO {
+	return &SQLiteDAO{db: dao.db, user: uuid}
+}
+
 // Concert methods
 func (dao *SQLiteDAO) GetAllConcerts() ([]Concert, error) {
-	rows, err := dao.db.Query("SELECT COALESCE(artists, ''), COALESCE(people_went_with, ''), COALESCE(notes, '') FROM concerts")
+	rows, err := dao.db.Query("SELECT COALESCE(date, ''), COALESCE(artists, ''), COALESCE(people_went_with, ''), COALESCE(notes, '') FROM concerts WHERE user_uuid = ?", dao.user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query concerts: %w", err)
 	}
@@ -170,7 +552,7 @@ func (dao *SQLiteDAO) GetAllConcerts() ([]Concert, error) {
 	var concerts []Concert
 	for rows.Next() {
 		var concert Concert
-		err = rows.Scan(&concert.Artists, &concert.People, &concert.Notes)
+		err = rows.Scan(&concert.Date, &concert.Artists, &concert.People, &concert.Notes)
 		if err != nil {
 			log.Printf("Failed to scan concert row: %v", err)
 			continue
@@ -183,7 +565,7 @@ func (dao *SQLiteDAO) GetAllConcerts() ([]Concert, error) {
 
 // Movie methods
 func (dao *SQLiteDAO) GetAllMovies() ([]Movie, error) {
-	rows, err := dao.db.Query("SELECT COALESCE(title, ''), COALESCE(tier, '') FROM watched_movies")
+	rows, err := dao.db.Query("SELECT COALESCE(title, ''), COALESCE(tier, ''), COALESCE(position, 0), COALESCE(rating, ''), COALESCE(notes, ''), COALESCE(watched_date, '') FROM watched_movies WHERE user_uuid = ? ORDER BY position IS NULL, position, title", dao.user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query movies: %w", err)
 	}
@@ -192,7 +574,7 @@ func (dao *SQLiteDAO) GetAllMovies() ([]Movie, error) {
 	var movies []Movie
 	for rows.Next() {
 		var movie Movie
-		err = rows.Scan(&movie.Title, &movie.Tier)
+		err = rows.Scan(&movie.Title, &movie.Tier, &movie.Position, &movie.Rating, &movie.Notes, &movie.WatchedDate)
 		if err != nil {
 			log.Printf("Failed to scan movie row: %v", err)
 			continue
@@ -204,7 +586,7 @@ func (dao *SQLiteDAO) GetAllMovies() ([]Movie, error) {
 }
 
 func (dao *SQLiteDAO) GetMoviesByTier(tier string) ([]Movie, error) {
-	rows, err := dao.db.Query("SELECT COALESCE(title, ''), COALESCE(tier, '') FROM watched_movies WHERE tier = ?", tier)
+	rows, err := dao.db.Query("SELECT COALESCE(title, ''), COALESCE(tier, ''), COALESCE(position, 0), COALESCE(rating, ''), COALESCE(notes, ''), COALESCE(watched_date, '') FROM watched_movies WHERE user_uuid = ? AND tier = ? ORDER BY position IS NULL, position, title", dao.user, tier)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query movies by tier: %w", err)
 	}
@@ -213,7 +595,7 @@ func (dao *SQLiteDAO) GetMoviesByTier(tier string) ([]Movie, error) {
 	var movies []Movie
 	for rows.Next() {
 		var movie Movie
-		err = rows.Scan(&movie.Title, &movie.Tier)
+		err = rows.Scan(&movie.Title, &movie.Tier, &movie.Position, &movie.Rating, &movie.Notes, &movie.WatchedDate)
 		if err != nil {
 			log.Printf("Failed to scan movie row: %v", err)
 			continue
@@ -224,185 +606,2376 @@ func (dao *SQLiteDAO) GetMoviesByTier(tier string) ([]Movie, error) {
 	return movies, nil
 }
 
-// Book methods
-func (dao *SQLiteDAO) GetAllBooks() ([]Book, error) {
-	rows, err := dao.db.Query("SELECT COALESCE(title, ''), COALESCE(rating, 0), COALESCE(pages, 0), COALESCE(author, ''), COALESCE(series, ''), COALESCE(finished, 0) FROM books")
+func (dao *SQLiteDAO) CreateMovie(movie Movie) error {
+	insertQuery := `INSERT INTO watched_movies (user_uuid, title, tier, position, rating, notes, watched_date)
+VALUES (?, ?, ?, (SELECT COALESCE(MAX(position), -1) + 1 FROM watched_movies WHERE user_uuid = ? AND tier = ?), ?, ?, ?)`
+	_, err := dao.db.Exec(insertQuery, dao.user, movie.Title, nullIfEmpty(movie.Tier), dao.user, movie.Tier, nullIfEmpty(movie.Rating), movie.Notes, nullIfEmpty(movie.WatchedDate))
 	if err != nil {
-		return nil, fmt.Errorf("failed to query books: %w", err)
+		if isSQLiteConflict(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to insert movie: %w", err)
 	}
-	defer rows.Close()
+	return nil
+}
 
-	var books []Book
-	for rows.Next() {
-		var book Book
-		err = rows.Scan(&book.Title, &book.Rating, &book.Pages, &book.Author, &book.Series, &book.Finished)
-		if err != nil {
-			log.Printf("Failed to scan book row: %v", err)
-			continue
-		}
-		books = append(books, book)
+// UpdateMovie replaces the movie stored under title. The title itself may change,
+// which fails with ErrConflict if another movie already uses the new one.
+// Position is managed by MoveMovie and ReorderTier; a movie whose tier changes here goes to the end of its new tier.
+func (dao *SQLiteDAO) UpdateMovie(title string, movie Movie) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	return books, nil
-}
+	var oldTier string
+	err = tx.QueryRow("SELECT COALESCE(tier, '') FROM watched_movies WHERE user_uuid = ? AND title = ?", dao.user, title).Scan(&oldTier)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query movie: %w", err)
+	}
 
-// Food methods
-func (dao *SQLiteDAO) GetAllFoodPlaces() ([]FoodPlace, error) {
-	rows, err := dao.db.Query("SELECT COALESCE(name, ''), COALESCE(location, ''), COALESCE(notes, ''), COALESCE(type, ''), COALESCE(category, '') FROM food_places ORDER BY name")
+	_, err = tx.Exec("UPDATE watched_movies SET title = ?, tier = ?, rating = ?, notes = ?, watched_date = ? WHERE user_uuid = ? AND title = ?",
+		movie.Title, nullIfEmpty(movie.Tier), nullIfEmpty(movie.Rating), movie.Notes, nullIfEmpty(movie.WatchedDate), dao.user, title)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query food places: %w", err)
+		if isSQLiteConflict(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to update movie: %w", err)
 	}
-	defer rows.Close()
 
-	var foodPlaces []FoodPlace
-	for rows.Next() {
-		var place FoodPlace
-		err = rows.Scan(&place.Name, &place.Location, &place.Notes, &place.Type, &place.Category)
+	if movie.Tier != oldTier {
+		err = dao.placeMovie(tx, movie.Title, oldTier, movie.Tier, math.MaxInt)
 		if err != nil {
-			log.Printf("Failed to scan food place row: %v", err)
-			continue
+			return err
 		}
-		foodPlaces = append(foodPlaces, place)
 	}
 
-	return foodPlaces, nil
+	err = renameComparedItem(tx, dao.user, "movies", title, movie.Title, func(n int) string { return "?" })
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (dao *SQLiteDAO) GetFoodPlacesByLocation(location string) ([]FoodPlace, error) {
-	rows, err := dao.db.Query("SELECT COALESCE(name, ''), COALESCE(location, ''), COALESCE(notes, ''), COALESCE(type, ''), COALESCE(category, '') FROM food_places WHERE location = ? ORDER BY name", location)
+func (dao *SQLiteDAO) DeleteMovie(title string) error {
+	tx, err := dao.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to query food places by location: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var foodPlaces []FoodPlace
-	for rows.Next() {
-		var place FoodPlace
-		err = rows.Scan(&place.Name, &place.Location, &place.Notes, &place.Type, &place.Category)
-		if err != nil {
-			log.Printf("Failed to scan food place row: %v", err)
-			continue
-		}
-		foodPlaces = append(foodPlaces, place)
+	result, err := tx.Exec(`DELETE FROM watched_movies WHERE user_uuid = ? AND title = ?`, dao.user, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete movie: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return err
 	}
 
-	return foodPlaces, nil
+	err = forgetComparedItem(tx, dao.user, "movies", title, func(n int) string { return "?" })
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// People methods
-func (dao *SQLiteDAO) GetAllPeople() ([]Person, error) {
-	rows, err := dao.db.Query("SELECT id, COALESCE(first, ''), COALESCE(middle, ''), COALESCE(last, ''), COALESCE(address, ''), COALESCE(birth_day, 0), COALESCE(birth_month, 0), COALESCE(birth_year, 0), COALESCE(gift_ideas, ''), COALESCE(email, ''), COALESCE(category, ''), COALESCE(notes, '') FROM people ORDER BY last, first")
+// MoveMovie moves a movie into tier at position (0 is the top), shifting the movies around it.
+// Positions past the end of the tier place the movie last.
+func (dao *SQLiteDAO) MoveMovie(title, tier string, position int) error {
+	tx, err := dao.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to query people: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var people []Person
-	for rows.Next() {
-		var person Person
-		err = rows.Scan(
-			&person.ID,
-			&person.First,
-			&person.Middle,
-			&person.Last,
-			&person.Address,
-			&person.BirthDay,
-			&person.BirthMonth,
-			&person.BirthYear,
-			&person.GiftIdeas,
-			&person.Email,
-			&person.Category,
-			&person.Notes,
-		)
+	var oldTier string
+	err = tx.QueryRow("SELECT COALESCE(tier, '') FROM watched_movies WHERE user_uuid = ? AND title = ?", dao.user, title).Scan(&oldTier)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to query movie: %w", err)
+	}
+
+	err = dao.placeMovie(tx, title, oldTier, tier, position)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// placeMovie puts a movie into tier at position, renumbering the movies around it and
+// closing the gap it left in oldTier. Movies without a tier aren't ordered.
+func (dao *SQLiteDAO) placeMovie(tx *sql.Tx, title, oldTier, tier string, position int) error {
+	if tier != "" {
+		titles, err := queryStrings(tx, "SELECT title FROM watched_movies WHERE user_uuid = ? AND tier = ? AND title <> ? ORDER BY position IS NULL, position, title", dao.user, tier, title)
 		if err != nil {
-			log.Printf("Failed to scan person row: %v", err)
-			continue
+			return fmt.Errorf("failed to query tier: %w", err)
+		}
+		position = max(0, min(position, len(titles)))
+		titles = slices.Insert(titles, position, title)
+
+		for i, t := range titles {
+			_, err = tx.Exec("UPDATE watched_movies SET tier = ?, position = ? WHERE user_uuid = ? AND title = ?", tier, i, dao.user, t)
+			if err != nil {
+				return fmt.Errorf("failed to update position: %w", err)
+			}
 		}
-		people = append(people, person)
 	}
 
-	return people, nil
+	// Close the gap left in the old tier
+	if oldTier != "" && oldTier != tier {
+		rest, err := queryStrings(tx, "SELECT title FROM watched_movies WHERE user_uuid = ? AND tier = ? ORDER BY position IS NULL, position, title", dao.user, oldTier)
+		if err != nil {
+			return fmt.Errorf("failed to query tier: %w", err)
+		}
+		for i, t := range rest {
+			_, err = tx.Exec("UPDATE watched_movies SET position = ? WHERE user_uuid = ? AND title = ?", i, dao.user, t)
+			if err != nil {
+				return fmt.Errorf("failed to update position: %w", err)
+			}
+		}
+	}
+
+	return nil
 }
 
-// TV methods
-func (dao *SQLiteDAO) GetAllTVShows() ([]TVShow, error) {
-	rows, err := dao.db.Query("SELECT COALESCE(title, ''), COALESCE(notes, ''), COALESCE(seasons_watched, '') FROM tv_shows")
+// ReorderTier sets the order of every movie in tier. titles must contain exactly the
+// movies currently in the tier, otherwise ErrConflict is returned and nothing changes.
+func (dao *SQLiteDAO) ReorderTier(tier string, titles []string) error {
+	tx, err := dao.db.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to query TV shows: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer rows.Close()
+	defer tx.Rollback()
 
-	var tvShows []TVShow
-	for rows.Next() {
-		var show TVShow
-		err = rows.Scan(&show.Title, &show.Notes, &show.SeasonsWatched)
+	current, err := queryStrings(tx, "SELECT title FROM watched_movies WHERE user_uuid = ? AND tier = ?", dao.user, tier)
+	if err != nil {
+		return fmt.Errorf("failed to query tier: %w", err)
+	}
+	if !sameStrings(current, titles) {
+		return ErrConflict
+	}
+
+	for i, t := range titles {
+		_, err = tx.Exec("UPDATE watched_movies SET position = ? WHERE user_uuid = ? AND title = ?", i, dao.user, t)
 		if err != nil {
-			log.Printf("Failed to scan TV show row: %v", err)
-			continue
+			return fmt.Errorf("failed to update position: %w", err)
 		}
-		tvShows = append(tvShows, show)
 	}
 
-	return tvShows, nil
+	return tx.Commit()
 }
 
-// Journal methods
-func (dao *SQLiteDAO) GetAllJournalEntries() ([]JournalEntry, error) {
-	rows, err := dao.db.Query("SELECT id, COALESCE(created, ''), COALESCE(title, ''), COALESCE(entry, ''), COALESCE(tags, ''), COALESCE(photos, '') FROM journal_entries ORDER BY created DESC")
+// Comparison methods
+func (dao *SQLiteDAO) GetComparisonPair(category string) (*ComparisonPair, error) {
+	query := fmt.Sprintf("SELECT title FROM %s WHERE user_uuid = ? AND title IS NOT NULL ORDER BY RANDOM() LIMIT 2", comparableTables[category])
+	rows, err := dao.db.Query(query, dao.user)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query journal entries: %w", err)
+		return nil, fmt.Errorf("failed to query comparison pair: %w", err)
 	}
 	defer rows.Close()
 
-	var entries []JournalEntry
+	var titles []string
 	for rows.Next() {
-		var entry JournalEntry
-		err = rows.Scan(&entry.ID, &entry.Created, &entry.Title, &entry.Entry, &entry.Tags, &entry.Photos)
+		var title string
+		err = rows.Scan(&title)
 		if err != nil {
-			log.Printf("Failed to scan journal entry row: %v", err)
-			continue
+			return nil, fmt.Errorf("failed to scan comparison pair: %w", err)
 		}
-		entries = append(entries, entry)
+		titles = append(titles, title)
+	}
+	if len(titles) < 2 {
+		return nil, ErrNotFound
 	}
 
-	return entries, nil
+	return &ComparisonPair{Category: category, A: titles[0], B: titles[1]}, nil
 }
 
-func (dao *SQLiteDAO) CreateJournalEntry(title, entry, tags, photos string) error {
-	insertQuery := `INSERT INTO journal_entries (title, entry, tags, photos) VALUES (?, ?, ?, ?)`
-	_, err := dao.db.Exec(insertQuery, title, entry, tags, photos)
+// RecordComparison stores the outcome of a comparison and updates both Elo scores
+func (dao *SQLiteDAO) RecordComparison(category, winner, loser string) error {
+	tx, err := dao.db.Begin()
 	if err != nil {
-		return fmt.Errorf("failed to insert journal entry: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	return nil
-}
+	defer tx.Rollback()
 
-// Photo methods
-func (dao *SQLiteDAO) CreatePhoto(fileName string, bytes []byte) (int, error) {
-	insertQuery := `INSERT INTO files (file_name, bytes) VALUES (?, ?)`
-	result, err := dao.db.Exec(insertQuery, fileName, bytes)
-	if err != nil {
-		return 0, fmt.Errorf("failed to insert photo: %w", err)
+	// A title may be stored more than once, so each one is looked up on its own
+	existsQuery := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE user_uuid = ? AND title = ?)", comparableTables[category])
+	for _, title := range []string{winner, loser} {
+		var exists bool
+		err = tx.QueryRow(existsQuery, dao.user, title).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to query compared items: %w", err)
+		}
+		if !exists {
+			return ErrNotFound
+		}
 	}
 
-	id, err := result.LastInsertId()
+	scores := make([]float64, 2)
+	counts := make([]int, 2)
+	for i, title := range []string{winner, loser} {
+		err = tx.QueryRow("SELECT score, comparisons FROM elo_scores WHERE user_uuid = ? AND category = ? AND title = ?", dao.user, category, title).Scan(&scores[i], &counts[i])
+		if err == sql.ErrNoRows {
+			scores[i], err = EloInitialScore, nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to query score: %w", err)
+		}
+	}
+	scores[0], scores[1] = EloUpdate(scores[0], scores[1])
+
+	upsertQuery := `INSERT INTO elo_scores (user_uuid, category, title, score, comparisons) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_uuid, category, title) DO UPDATE SET score = excluded.score, comparisons = excluded.comparisons`
+	for i, title := range []string{winner, loser} {
+		_, err = tx.Exec(upsertQuery, dao.user, category, title, scores[i], counts[i]+1)
+		if err != nil {
+			return fmt.Errorf("failed to update score: %w", err)
+		}
+	}
+
+	_, err = tx.Exec("INSERT INTO comparisons (user_uuid, category, winner, loser) VALUES (?, ?, ?, ?)", dao.user, category, winner, loser)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+		return fmt.Errorf("failed to insert comparison: %w", err)
 	}
 
-	return int(id), nil
+	return tx.Commit()
 }
 
-func (dao *SQLiteDAO) GetPhotoByID(id int) (*Photo, error) {
-	var photo Photo
-	err := dao.db.QueryRow("SELECT id, COALESCE(file_name, ''), bytes, COALESCE(created, '') FROM files WHERE id = ?", id).
-		Scan(&photo.ID, &photo.FileName, &photo.Bytes, &photo.Created)
+// GetComparisonRanking lists every item in the category from highest to lowest score.
+// Items that have never been compared keep the initial score.
+func (dao *SQLiteDAO) GetComparisonRanking(category string) ([]RankedItem, error) {
+	query := fmt.Sprintf(`SELECT t.title, COALESCE(s.score, ?), COALESCE(s.comparisons, 0)
+FROM %s t LEFT JOIN elo_scores s ON s.user_uuid = t.user_uuid AND s.category = ? AND s.title = t.title
+WHERE t.user_uuid = ? AND t.title IS NOT NULL ORDER BY 2 DESC, t.title`, comparableTables[category])
+	rows, err := dao.db.Query(query, EloInitialScore, category, dao.user)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("photo not found")
-		}
-		return nil, fmt.Errorf("failed to query photo: %w", err)
+		return nil, fmt.Errorf("failed to query ranking: %w", err)
+	}
+	defer rows.Close()
+
+	var ranking []RankedItem
+	for rows.Next() {
+		var item RankedItem
+		err = rows.Scan(&item.Title, &item.Score, &item.Comparisons)
+		if err != nil {
+			log.Printf("Failed to scan ranking row: %v", err)
+			continue
+		}
+		item.Rank = len(ranking) + 1
+		ranking = append(ranking, item)
+	}
+
+	return ranking, nil
+}
+
+// Theater movie methods
+const sqliteTheaterMovieSelect = `SELECT t.id, COALESCE(t.title, ''), COALESCE(t.date, ''), COALESCE(t.people_went_with, ''), COALESCE(t.notes, ''),
+w.title, COALESCE(w.tier, ''), COALESCE(w.position, 0), COALESCE(w.rating, ''), COALESCE(w.notes, ''), COALESCE(w.watched_date, '')
+FROM theater_movies t LEFT JOIN watched_movies w ON w.user_uuid = t.user_uuid AND w.title = t.title`
+
+// GetTheaterMovies lists theater movies, newest first. A year of 0 leaves that end of the range open.
+func (dao *SQLiteDAO) GetTheaterMovies(fromYear, toYear int) ([]TheaterMovie, error) {
+	where := []string{"t.user_uuid = ?"}
+	args := []any{dao.user}
+	if fromYear > 0 {
+		args = append(args, fmt.Sprintf("%04d-01-01", fromYear))
+		where = append(where, "t.date >= ?")
+	}
+	if toYear > 0 {
+		args = append(args, fmt.Sprintf("%04d-01-01", toYear+1))
+		where = append(where, "t.date < ?")
+	}
+
+	query := sqliteTheaterMovieSelect + " WHERE " + strings.Join(where, " AND ") + " ORDER BY t.date DESC, t.id DESC"
+
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query theater movies: %w", err)
+	}
+	defer rows.Close()
+
+	var movies []TheaterMovie
+	for rows.Next() {
+		movie, err := scanTheaterMovie(rows)
+		if err != nil {
+			log.Printf("Failed to scan theater movie row: %v", err)
+			continue
+		}
+		movies = append(movies, movie)
+	}
+
+	links, err := queryPeopleLinks(dao.db, `SELECT l.theater_movie_id, l.person_id FROM theater_movie_people l
+JOIN theater_movies t ON t.id = l.theater_movie_id WHERE t.user_uuid = ?`, dao.user)
+	if err != nil {
+		return nil, err
+	}
+	for i := range movies {
+		movies[i].PeopleIDs = links[movies[i].ID]
+	}
+
+	return movies, nil
+}
+
+func (dao *SQLiteDAO) GetTheaterMovie(id int) (*TheaterMovie, error) {
+	movie, err := scanTheaterMovie(dao.db.QueryRow(sqliteTheaterMovieSelect+" WHERE t.user_uuid = ? AND t.id = ?", dao.user, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query theater movie: %w", err)
+	}
+
+	links, err := queryPeopleLinks(dao.db, "SELECT theater_movie_id, person_id FROM theater_movie_people WHERE theater_movie_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	movie.PeopleIDs = links[id]
+
+	return &movie, nil
+}
+
+func (dao *SQLiteDAO) CreateTheaterMovie(movie TheaterMovie) (int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertQuery := `INSERT INTO theater_movies (user_uuid, title, date, people_went_with, notes)
+VALUES (?, ?, ?, ?, ?) RETURNING id`
+	var id int
+	err = tx.QueryRow(insertQuery, dao.user, movie.Title, nullIfEmpty(movie.Date), movie.People, movie.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert theater movie: %w", err)
+	}
+
+	err = dao.replacePeopleLinks(tx, "theater_movie_people", "theater_movie_id", id, movie.PeopleIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// UpdateTheaterMovie replaces a theater movie. Its people links are only replaced when PeopleIDs is non-nil.
+func (dao *SQLiteDAO) UpdateTheaterMovie(id int, movie TheaterMovie) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `UPDATE theater_movies SET title = ?, date = ?, people_went_with = ?, notes = ? WHERE user_uuid = ? AND id = ?`
+	result, err := tx.Exec(updateQuery, movie.Title, nullIfEmpty(movie.Date), movie.People, movie.Notes, dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to update theater movie: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	if movie.PeopleIDs != nil {
+		err = dao.replacePeopleLinks(tx, "theater_movie_people", "theater_movie_id", id, movie.PeopleIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (dao *SQLiteDAO) DeleteTheaterMovie(id int) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM theater_movie_people WHERE theater_movie_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete theater movie people: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM theater_movies WHERE user_uuid = ? AND id = ?", dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete theater movie: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Travel methods
+const sqliteTripSelect = `SELECT id, COALESCE(title, ''), COALESCE(start_date, dates, ''), COALESCE(end_date, ''), COALESCE(places, ''), COALESCE(people_went_with, ''), COALESCE(notes, '') FROM travel`
+
+// GetTrips lists trips, most recent first. A year of 0 leaves that end of the range open.
+func (dao *SQLiteDAO) GetTrips(fromYear, toYear int) ([]Trip, error) {
+	where := []string{"user_uuid = ?"}
+	args := []any{dao.user}
+	if fromYear > 0 {
+		args = append(args, fmt.Sprintf("%04d-01-01", fromYear))
+		where = append(where, "COALESCE(start_date, dates) >= ?")
+	}
+	if toYear > 0 {
+		args = append(args, fmt.Sprintf("%04d-01-01", toYear+1))
+		where = append(where, "COALESCE(start_date, dates) < ?")
+	}
+
+	query := sqliteTripSelect + " WHERE " + strings.Join(where, " AND ") + " ORDER BY COALESCE(start_date, dates) DESC, id DESC"
+
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trips: %w", err)
+	}
+	defer rows.Close()
+
+	var trips []Trip
+	var legacyPlaces []string
+	for rows.Next() {
+		trip, places, err := scanTrip(rows)
+		if err != nil {
+			log.Printf("Failed to scan trip row: %v", err)
+			continue
+		}
+		trips = append(trips, trip)
+		legacyPlaces = append(legacyPlaces, places)
+	}
+
+	places, err := queryTripPlaces(dao.db, `SELECT p.trip_id, p.name, p.latitude, p.longitude FROM trip_places p
+JOIN travel t ON t.id = p.trip_id WHERE t.user_uuid = ? ORDER BY p.trip_id, p.position`, dao.user)
+	if err != nil {
+		return nil, err
+	}
+	links, err := queryPeopleLinks(dao.db, "SELECT l.travel_id, l.person_id FROM travel_people l JOIN travel t ON t.id = l.travel_id WHERE t.user_uuid = ?", dao.user)
+	if err != nil {
+		return nil, err
+	}
+	for i := range trips {
+		trips[i].Places = places[trips[i].ID]
+		if trips[i].Places == nil {
+			trips[i].Places = legacyTripPlaces(legacyPlaces[i])
+		}
+		trips[i].PeopleIDs = links[trips[i].ID]
+	}
+
+	return trips, nil
+}
+
+func (dao *SQLiteDAO) GetTrip(id int) (*Trip, error) {
+	trip, legacyPlaces, err := scanTrip(dao.db.QueryRow(sqliteTripSelect+" WHERE user_uuid = ? AND id = ?", dao.user, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trip: %w", err)
+	}
+
+	places, err := queryTripPlaces(dao.db, "SELECT trip_id, name, latitude, longitude FROM trip_places WHERE trip_id = ? ORDER BY position", id)
+	if err != nil {
+		return nil, err
+	}
+	trip.Places = places[id]
+	if trip.Places == nil {
+		trip.Places = legacyTripPlaces(legacyPlaces)
+	}
+
+	links, err := queryPeopleLinks(dao.db, "SELECT travel_id, person_id FROM travel_people WHERE travel_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	trip.PeopleIDs = links[id]
+
+	return &trip, nil
+}
+
+func (dao *SQLiteDAO) CreateTrip(trip Trip) (int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertQuery := `INSERT INTO travel (user_uuid, title, start_date, end_date, dates, places, people_went_with, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
+	var id int
+	err = tx.QueryRow(insertQuery, dao.user, trip.Title, nullIfEmpty(trip.StartDate), nullIfEmpty(trip.EndDate), nullIfEmpty(trip.StartDate),
+		tripPlaceNames(trip.Places), trip.People, trip.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert trip: %w", err)
+	}
+
+	err = dao.replaceTripPlaces(tx, id, trip.Places)
+	if err != nil {
+		return 0, err
+	}
+	err = dao.replacePeopleLinks(tx, "travel_people", "travel_id", id, trip.PeopleIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// UpdateTrip replaces a trip and its stops. Its people links are only replaced when PeopleIDs is non-nil.
+func (dao *SQLiteDAO) UpdateTrip(id int, trip Trip) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `UPDATE travel SET title = ?, start_date = ?, end_date = ?, dates = ?, places = ?, people_went_with = ?, notes = ? WHERE user_uuid = ? AND id = ?`
+	result, err := tx.Exec(updateQuery, trip.Title, nullIfEmpty(trip.StartDate), nullIfEmpty(trip.EndDate), nullIfEmpty(trip.StartDate),
+		tripPlaceNames(trip.Places), trip.People, trip.Notes, dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to update trip: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	err = dao.replaceTripPlaces(tx, id, trip.Places)
+	if err != nil {
+		return err
+	}
+	if trip.PeopleIDs != nil {
+		err = dao.replacePeopleLinks(tx, "travel_people", "travel_id", id, trip.PeopleIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (dao *SQLiteDAO) DeleteTrip(id int) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM trip_places WHERE trip_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete trip places: %w", err)
+	}
+	_, err = tx.Exec("DELETE FROM travel_people WHERE travel_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete trip people: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM travel WHERE user_uuid = ? AND id = ?", dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete trip: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceTripPlaces rewrites a trip's stops in the order given
+func (dao *SQLiteDAO) replaceTripPlaces(tx *sql.Tx, tripID int, places []TripPlace) error {
+	_, err := tx.Exec("DELETE FROM trip_places WHERE trip_id = ?", tripID)
+	if err != nil {
+		return fmt.Errorf("failed to clear trip places: %w", err)
+	}
+
+	for i, place := range places {
+		_, err = tx.Exec("INSERT INTO trip_places (trip_id, position, name, latitude, longitude) VALUES (?, ?, ?, ?, ?)",
+			tripID, i, place.Name, place.Latitude, place.Longitude)
+		if err != nil {
+			return fmt.Errorf("failed to insert trip place: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Life event methods
+const sqliteLifeEventSelect = `SELECT id, COALESCE(title, ''), COALESCE(year, 0), COALESCE(month, 0), COALESCE(day, 0), COALESCE(notes, '') FROM life_events`
+
+// GetLifeEvents lists life events oldest first. Events missing a month or day sort before
+// the dated events of the same year or month.
+func (dao *SQLiteDAO) GetLifeEvents() ([]LifeEvent, error) {
+	rows, err := dao.db.Query(sqliteLifeEventSelect+" WHERE user_uuid = ? ORDER BY year IS NULL, year, COALESCE(month, 0), COALESCE(day, 0), id", dao.user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query life events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []LifeEvent
+	for rows.Next() {
+		event, err := scanLifeEvent(rows)
+		if err != nil {
+			log.Printf("Failed to scan life event row: %v", err)
+			continue
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func (dao *SQLiteDAO) GetLifeEvent(id int) (*LifeEvent, error) {
+	event, err := scanLifeEvent(dao.db.QueryRow(sqliteLifeEventSelect+" WHERE user_uuid = ? AND id = ?", dao.user, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query life event: %w", err)
+	}
+	return &event, nil
+}
+
+func (dao *SQLiteDAO) CreateLifeEvent(event LifeEvent) (int, error) {
+	insertQuery := `INSERT INTO life_events (user_uuid, title, year, month, day, notes)
+VALUES (?, ?, ?, ?, ?, ?) RETURNING id`
+	var id int
+	err := dao.db.QueryRow(insertQuery, dao.user, event.Title, nullIfZero(event.Year), nullIfZero(event.Month), nullIfZero(event.Day), event.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert life event: %w", err)
+	}
+	return id, nil
+}
+
+func (dao *SQLiteDAO) UpdateLifeEvent(id int, event LifeEvent) error {
+	updateQuery := `UPDATE life_events SET title = ?, year = ?, month = ?, day = ?, notes = ? WHERE user_uuid = ? AND id = ?`
+	result, err := dao.db.Exec(updateQuery, event.Title, nullIfZero(event.Year), nullIfZero(event.Month), nullIfZero(event.Day), event.Notes, dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to update life event: %w", err)
+	}
+	return expectAffected(result)
+}
+
+func (dao *SQLiteDAO) DeleteLifeEvent(id int) error {
+	result, err := dao.db.Exec("DELETE FROM life_events WHERE user_uuid = ? AND id = ?", dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete life event: %w", err)
+	}
+	return expectAffected(result)
+}
+
+// Memory methods
+const sqliteMemorySelect = `SELECT id, COALESCE(date, ''), COALESCE(notes, ''), COALESCE(involved_people, '') FROM random_memories`
+
+// GetMemories lists memories, newest first. from and to are inclusive YYYY-MM-DD dates; an empty one leaves that end open.
+func (dao *SQLiteDAO) GetMemories(from, to string) ([]Memory, error) {
+	where := []string{"user_uuid = ?"}
+	args := []any{dao.user}
+	if from != "" {
+		args = append(args, from)
+		where = append(where, "date >= ?")
+	}
+	if to != "" {
+		args = append(args, to)
+		where = append(where, "date <= ?")
+	}
+
+	query := sqliteMemorySelect + " WHERE " + strings.Join(where, " AND ") + " ORDER BY date DESC, id DESC"
+
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memories: %w", err)
+	}
+	defer rows.Close()
+
+	var memories []Memory
+	for rows.Next() {
+		memory, err := scanMemory(rows)
+		if err != nil {
+			log.Printf("Failed to scan memory row: %v", err)
+			continue
+		}
+		memories = append(memories, memory)
+	}
+
+	links, err := queryPeopleLinks(dao.db, "SELECT l.memory_id, l.person_id FROM memory_people l JOIN random_memories m ON m.id = l.memory_id WHERE m.user_uuid = ?", dao.user)
+	if err != nil {
+		return nil, err
+	}
+	for i := range memories {
+		memories[i].PeopleIDs = links[memories[i].ID]
+	}
+
+	return memories, nil
+}
+
+func (dao *SQLiteDAO) GetMemory(id int) (*Memory, error) {
+	return dao.getMemory(sqliteMemorySelect+" WHERE user_uuid = ? AND id = ?", dao.user, id)
+}
+
+// GetRandomMemory picks any one memory, returning ErrNotFound when there are none
+func (dao *SQLiteDAO) GetRandomMemory() (*Memory, error) {
+	return dao.getMemory(sqliteMemorySelect+" WHERE user_uuid = ? ORDER BY RANDOM() LIMIT 1", dao.user)
+}
+
+// getMemory reads the memory selected by query along with its people links
+func (dao *SQLiteDAO) getMemory(query string, args ...any) (*Memory, error) {
+	memory, err := scanMemory(dao.db.QueryRow(query, args...))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query memory: %w", err)
+	}
+
+	links, err := queryPeopleLinks(dao.db, "SELECT memory_id, person_id FROM memory_people WHERE memory_id = ?", memory.ID)
+	if err != nil {
+		return nil, err
+	}
+	memory.PeopleIDs = links[memory.ID]
+
+	return &memory, nil
+}
+
+func (dao *SQLiteDAO) CreateMemory(memory Memory) (int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertQuery := `INSERT INTO random_memories (user_uuid, date, notes, involved_people)
+VALUES (?, ?, ?, ?) RETURNING id`
+	var id int
+	err = tx.QueryRow(insertQuery, dao.user, nullIfEmpty(memory.Date), memory.Notes, memory.People).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert memory: %w", err)
+	}
+
+	err = dao.replacePeopleLinks(tx, "memory_people", "memory_id", id, memory.PeopleIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// UpdateMemory replaces a memory. Its people links are only replaced when PeopleIDs is non-nil.
+func (dao *SQLiteDAO) UpdateMemory(id int, memory Memory) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `UPDATE random_memories SET date = ?, notes = ?, involved_people = ? WHERE user_uuid = ? AND id = ?`
+	result, err := tx.Exec(updateQuery, nullIfEmpty(memory.Date), memory.Notes, memory.People, dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to update memory: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	if memory.PeopleIDs != nil {
+		err = dao.replacePeopleLinks(tx, "memory_people", "memory_id", id, memory.PeopleIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (dao *SQLiteDAO) DeleteMemory(id int) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM memory_people WHERE memory_id = ?", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete memory people: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM random_memories WHERE user_uuid = ? AND id = ?", dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete memory: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Video game methods
+const sqliteVideoGameSelect = `SELECT id, COALESCE(title, ''), COALESCE(platform, ''), COALESCE(status, 'backlog'), COALESCE(hours_played, 0), COALESCE(rating, 0), COALESCE(multiplayer, 0), COALESCE(notes, '') FROM video_games`
+
+// GetVideoGames lists video games by title. An empty status or platform matches every game.
+func (dao *SQLiteDAO) GetVideoGames(status, platform string) ([]VideoGame, error) {
+	where := []string{"user_uuid = ?"}
+	args := []any{dao.user}
+	if status != "" {
+		args = append(args, status)
+		where = append(where, "COALESCE(status, 'backlog') = ?")
+	}
+	if platform != "" {
+		args = append(args, platform)
+		where = append(where, "LOWER(platform) = LOWER(?)")
+	}
+
+	query := sqliteVideoGameSelect + " WHERE " + strings.Join(where, " AND ") + " ORDER BY title"
+
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video games: %w", err)
+	}
+	defer rows.Close()
+
+	var games []VideoGame
+	for rows.Next() {
+		game, err := scanVideoGame(rows)
+		if err != nil {
+			log.Printf("Failed to scan video game row: %v", err)
+			continue
+		}
+		games = append(games, game)
+	}
+
+	return games, nil
+}
+
+func (dao *SQLiteDAO) GetVideoGame(id int) (*VideoGame, error) {
+	game, err := scanVideoGame(dao.db.QueryRow(sqliteVideoGameSelect+" WHERE user_uuid = ? AND id = ?", dao.user, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video game: %w", err)
+	}
+	return &game, nil
+}
+
+func (dao *SQLiteDAO) CreateVideoGame(game VideoGame) (int, error) {
+	insertQuery := `INSERT INTO video_games (user_uuid, title, platform, status, hours_played, rating, multiplayer, notes)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
+	var id int
+	err := dao.db.QueryRow(insertQuery, dao.user, game.Title, game.Platform, game.Status, game.HoursPlayed, game.Rating, game.Multiplayer, game.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert video game: %w", err)
+	}
+	return id, nil
+}
+
+func (dao *SQLiteDAO) UpdateVideoGame(id int, game VideoGame) error {
+	updateQuery := `UPDATE video_games SET title = ?, platform = ?, status = ?, hours_played = ?, rating = ?, multiplayer = ?, notes = ? WHERE user_uuid = ? AND id = ?`
+	result, err := dao.db.Exec(updateQuery, game.Title, game.Platform, game.Status, game.HoursPlayed, game.Rating, game.Multiplayer, game.Notes, dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to update video game: %w", err)
+	}
+	return expectAffected(result)
+}
+
+func (dao *SQLiteDAO) DeleteVideoGame(id int) error {
+	result, err := dao.db.Exec("DELETE FROM video_games WHERE user_uuid = ? AND id = ?", dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete video game: %w", err)
+	}
+	return expectAffected(result)
+}
+
+// Book methods
+const sqliteBookSelect = `SELECT COALESCE(title, ''), COALESCE(rating, 0), COALESCE(pages, 0), COALESCE(author, ''), COALESCE(series, ''), COALESCE(series_sequence, 0),
+COALESCE(finished, 0), COALESCE(date_finished, ''), COALESCE(owned, 0), COALESCE(current_page, 0) FROM books`
+
+// GetBooks lists books by title
+func (dao *SQLiteDAO) GetBooks(filter BookFilter) ([]Book, error) {
+	where := []string{"user_uuid = ?"}
+	args := []any{dao.user}
+	if filter.Finished != nil {
+		args = append(args, *filter.Finished)
+		where = append(where, "COALESCE(finished, 0) = ?")
+	}
+	if filter.Owned != nil {
+		args = append(args, *filter.Owned)
+		where = append(where, "COALESCE(owned, 0) = ?")
+	}
+	if filter.Author != "" {
+		args = append(args, filter.Author)
+		where = append(where, "LOWER(author) = LOWER(?)")
+	}
+
+	query := sqliteBookSelect + " WHERE " + strings.Join(where, " AND ") + " ORDER BY title"
+	return dao.queryBooks(query, args...)
+}
+
+func (dao *SQLiteDAO) GetBook(title string) (*Book, error) {
+	book, err := scanBook(dao.db.QueryRow(sqliteBookSelect+" WHERE user_uuid = ? AND title = ?", dao.user, title))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query book: %w", err)
+	}
+	return &book, nil
+}
+
+func (dao *SQLiteDAO) CreateBook(book Book) error {
+	insertQuery := `INSERT INTO books (user_uuid, title, rating, pages, author, series, series_sequence, finished, date_finished, owned, current_page)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := dao.db.Exec(insertQuery, dao.user, book.Title, book.Rating, book.Pages, book.Author, nullIfEmpty(book.Series), nullIfZero(book.SeriesSequence),
+		book.Finished, nullIfEmpty(book.DateFinished), book.Owned, book.CurrentPage)
+	if err != nil {
+		if isSQLiteConflict(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to insert book: %w", err)
+	}
+	return nil
+}
+
+// UpdateBook replaces the book stored under title. The title itself may change,
+// which fails with ErrConflict if another book already uses the new one.
+func (dao *SQLiteDAO) UpdateBook(title string, book Book) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `UPDATE books SET title = ?, rating = ?, pages = ?, author = ?, series = ?, series_sequence = ?,
+finished = ?, date_finished = ?, owned = ?, current_page = ? WHERE user_uuid = ? AND title = ?`
+	result, err := tx.Exec(updateQuery, book.Title, book.Rating, book.Pages, book.Author, nullIfEmpty(book.Series), nullIfZero(book.SeriesSequence),
+		book.Finished, nullIfEmpty(book.DateFinished), book.Owned, book.CurrentPage, dao.user, title)
+	if err != nil {
+		if isSQLiteConflict(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to update book: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return err
+	}
+
+	err = renameComparedItem(tx, dao.user, "books", title, book.Title, func(n int) string { return "?" })
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (dao *SQLiteDAO) DeleteBook(title string) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM reading_sessions WHERE user_uuid = ? AND book_title = ?", dao.user, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete reading sessions: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM books WHERE user_uuid = ? AND title = ?", dao.user, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete book: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return err
+	}
+
+	err = forgetComparedItem(tx, dao.user, "books", title, func(n int) string { return "?" })
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetSeries lists the books of a series in reading order. Books without a sequence number come last.
+func (dao *SQLiteDAO) GetSeries(name string) ([]Book, error) {
+	return dao.queryBooks(sqliteBookSelect+" WHERE user_uuid = ? AND series = ? ORDER BY series_sequence IS NULL, series_sequence, title", dao.user, name)
+}
+
+func (dao *SQLiteDAO) queryBooks(query string, args ...any) ([]Book, error) {
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query books: %w", err)
+	}
+	defer rows.Close()
+
+	var books []Book
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			log.Printf("Failed to scan book row: %v", err)
+			continue
+		}
+		books = append(books, book)
+	}
+
+	return books, nil
+}
+
+// Reading methods
+func (dao *SQLiteDAO) GetReadingSessions(title string) ([]ReadingSession, error) {
+	rows, err := dao.db.Query("SELECT id, book_title, COALESCE(date, ''), pages_read, minutes FROM reading_sessions WHERE user_uuid = ? AND book_title = ? ORDER BY date DESC, id DESC", dao.user, title)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reading sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []ReadingSession
+	for rows.Next() {
+		var session ReadingSession
+		err = rows.Scan(&session.ID, &session.BookTitle, &session.Date, &session.PagesRead, &session.Minutes)
+		if err != nil {
+			log.Printf("Failed to scan reading session row: %v", err)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// CreateReadingSession logs a session and moves the book's current page forward by the
+// pages read, stopping at the last page when the page count is known.
+// Returns ErrNotFound if the book doesn't exist.
+func (dao *SQLiteDAO) CreateReadingSession(session ReadingSession) (int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	progressQuery := `UPDATE books SET current_page = CASE WHEN COALESCE(pages, 0) > 0
+THEN MIN(COALESCE(current_page, 0) + ?, pages) ELSE COALESCE(current_page, 0) + ? END WHERE user_uuid = ? AND title = ?`
+	result, err := tx.Exec(progressQuery, session.PagesRead, session.PagesRead, dao.user, session.BookTitle)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update current page: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return 0, err
+	}
+
+	var id int
+	insertQuery := `INSERT INTO reading_sessions (user_uuid, book_title, date, pages_read, minutes) VALUES (?, ?, ?, ?, ?) RETURNING id`
+	err = tx.QueryRow(insertQuery, dao.user, session.BookTitle, session.Date, session.PagesRead, session.Minutes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert reading session: %w", err)
+	}
+
+	return id, tx.Commit()
+}
+
+// DeleteReadingSession removes a logged session. The book's current page is left where it
+// is, since later sessions may have moved it on since.
+func (dao *SQLiteDAO) DeleteReadingSession(id int) error {
+	result, err := dao.db.Exec("DELETE FROM reading_sessions WHERE user_uuid = ? AND id = ?", dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete reading session: %w", err)
+	}
+	return expectAffected(result)
+}
+
+func (dao *SQLiteDAO) GetReadingGoal(year int) (*ReadingGoal, error) {
+	goal := ReadingGoal{Year: year}
+	err := dao.db.QueryRow("SELECT target, unit FROM reading_goals WHERE user_uuid = ? AND year = ?", dao.user, year).Scan(&goal.Target, &goal.Unit)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reading goal: %w", err)
+	}
+	return &goal, nil
+}
+
+// SetReadingGoal creates or replaces the goal for goal.Year
+func (dao *SQLiteDAO) SetReadingGoal(goal ReadingGoal) error {
+	upsertQuery := `INSERT INTO reading_goals (user_uuid, year, target, unit) VALUES (?, ?, ?, ?)
+ON CONFLICT (user_uuid, year) DO UPDATE SET target = excluded.target, unit = excluded.unit`
+	_, err := dao.db.Exec(upsertQuery, dao.user, goal.Year, goal.Target, goal.Unit)
+	if err != nil {
+		return fmt.Errorf("failed to save reading goal: %w", err)
+	}
+	return nil
+}
+
+// GetReadingStats totals a year of reading. Goal progress and pages per day depend on the
+// current date and are left for the caller to fill in.
+func (dao *SQLiteDAO) GetReadingStats(year int) (*ReadingStats, error) {
+	stats := &ReadingStats{Year: year, PagesPerMonth: make([]int, 12)}
+	start := fmt.Sprintf("%04d-01-01", year)
+	end := fmt.Sprintf("%04d-01-01", year+1)
+
+	goal, err := dao.GetReadingGoal(year)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, err
+	}
+	stats.Goal = goal
+
+	err = dao.db.QueryRow("SELECT COUNT(*) FROM books WHERE user_uuid = ? AND date_finished >= ? AND date_finished < ?", dao.user, start, end).Scan(&stats.BooksFinished)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count finished books: %w", err)
+	}
+
+	rows, err := dao.db.Query(`SELECT CAST(strftime('%m', date) AS INTEGER), SUM(pages_read), SUM(minutes), COUNT(*),
+SUM(CASE WHEN minutes > 0 THEN pages_read ELSE 0 END)
+FROM reading_sessions WHERE user_uuid = ? AND date >= ? AND date < ? GROUP BY 1`, dao.user, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query reading sessions: %w", err)
+	}
+	defer rows.Close()
+
+	timedPages := 0
+	for rows.Next() {
+		var month, pages, minutes, sessions, timed int
+		if err := rows.Scan(&month, &pages, &minutes, &sessions, &timed); err != nil {
+			return nil, fmt.Errorf("failed to scan reading sessions: %w", err)
+		}
+		if month >= 1 && month <= 12 {
+			stats.PagesPerMonth[month-1] = pages
+		}
+		stats.PagesRead += pages
+		stats.MinutesRead += minutes
+		stats.Sessions += sessions
+		timedPages += timed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read reading sessions: %w", err)
+	}
+
+	if stats.MinutesRead > 0 {
+		stats.PagesPerHour = float64(timedPages) / float64(stats.MinutesRead) * 60
+	}
+	return stats, nil
+}
+
+// Food methods
+
+// sqliteFoodPlaceSelect reads the columns scanFoodPlace expects, summarizing each place's visits
+const sqliteFoodPlaceSelect = `SELECT COALESCE(f.name, ''), COALESCE(f.location, ''), COALESCE(f.notes, ''), COALESCE(f.type, ''), COALESCE(f.category, ''),
+f.latitude, f.longitude, COALESCE(f.wishlist, 0),
+(SELECT COUNT(*) FROM food_visits v WHERE v.user_uuid = f.user_uuid AND v.place_name = f.name),
+(SELECT AVG(d.rating) FROM food_visit_dishes d JOIN food_visits v ON v.id = d.visit_id WHERE v.user_uuid = f.user_uuid AND v.place_name = f.name),
+(SELECT COALESCE(MAX(v.date), '') FROM food_visits v WHERE v.user_uuid = f.user_uuid AND v.place_name = f.name)
+FROM food_places f`
+
+func (dao *SQLiteDAO) GetAllFoodPlaces() ([]FoodPlace, error) {
+	return dao.queryFoodPlaces(sqliteFoodPlaceSelect+" WHERE f.user_uuid = ? ORDER BY f.name", dao.user)
+}
+
+func (dao *SQLiteDAO) GetFoodPlacesByLocation(location string) ([]FoodPlace, error) {
+	// Match the start of the full name, so "den" and "denver, co" both find Denver, CO, USA,
+	// or a whole region or country
+	query := sqliteFoodPlaceSelect + ` JOIN locations l ON l.id = f.location_id
+WHERE f.user_uuid = ? AND (l.name_key LIKE ? ESCAPE '\' OR LOWER(l.region) = ? OR LOWER(l.country) = ?) ORDER BY f.name`
+	search := strings.ToLower(strings.Join(strings.Fields(location), " "))
+	return dao.queryFoodPlaces(query, dao.user, locationPattern(search), search, search)
+}
+
+// GetFoodLocations lists the locations that have food places, busiest first
+func (dao *SQLiteDAO) GetFoodLocations() ([]Location, error) {
+	query := `SELECT l.id, l.city, l.region, l.country, COUNT(*) FROM locations l
+JOIN food_places f ON f.location_id = l.id WHERE f.user_uuid = ?
+GROUP BY l.id, l.city, l.region, l.country ORDER BY COUNT(*) DESC, l.name_key`
+	rows, err := dao.db.Query(query, dao.user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query locations: %w", err)
+	}
+	defer rows.Close()
+
+	var locations []Location
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan location: %w", err)
+		}
+		locations = append(locations, location)
+	}
+	return locations, rows.Err()
+}
+
+func (dao *SQLiteDAO) queryFoodPlaces(query string, args ...any) ([]FoodPlace, error) {
+	rows, err := dao.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query food places: %w", err)
+	}
+	defer rows.Close()
+
+	var foodPlaces []FoodPlace
+	for rows.Next() {
+		place, err := scanFoodPlace(rows)
+		if err != nil {
+			log.Printf("Failed to scan food place row: %v", err)
+			continue
+		}
+		foodPlaces = append(foodPlaces, place)
+	}
+
+	return foodPlaces, nil
+}
+
+// SetFoodPlaceCoordinates places a food place on the map, or removes it when both are nil
+func (dao *SQLiteDAO) SetFoodPlaceCoordinates(name string, latitude, longitude *float64) error {
+	result, err := dao.db.Exec("UPDATE food_places SET latitude = ?, longitude = ? WHERE user_uuid = ? AND name = ?", latitude, longitude, dao.user, name)
+	if err != nil {
+		return fmt.Errorf("failed to update food place coordinates: %w", err)
+	}
+	return expectAffected(result)
+}
+
+// CreateFoodPlace adds a place, filing it under a normalized location, and returns the
+// location name it was saved with
+func (dao *SQLiteDAO) CreateFoodPlace(place FoodPlace) (string, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	locationID, location, err := findOrCreateLocation(tx, place.Location, func(n int) string { return "?" })
+	if err != nil {
+		return "", err
+	}
+
+	insertQuery := `INSERT INTO food_places (user_uuid, name, location, location_id, notes, type, category, latitude, longitude, wishlist)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.Exec(insertQuery, dao.user, place.Name, location, locationID, place.Notes, place.Type, place.Category, place.Latitude, place.Longitude, place.Wishlist)
+	if err != nil {
+		if isSQLiteConflict(err) {
+			return "", ErrConflict
+		}
+		return "", fmt.Errorf("failed to insert food place: %w", err)
+	}
+
+	return location, tx.Commit()
+}
+
+// DeleteFoodPlace removes a place along with its visit log
+func (dao *SQLiteDAO) DeleteFoodPlace(name string) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"food_visit_dishes", "food_visit_people"} {
+		_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE visit_id IN (SELECT id FROM food_visits WHERE user_uuid = ? AND place_name = ?)", table), dao.user, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	_, err = tx.Exec("DELETE FROM food_visits WHERE user_uuid = ? AND place_name = ?", dao.user, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete food visits: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM food_places WHERE user_uuid = ? AND name = ?", dao.user, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete food place: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const sqliteFoodVisitSelect = "SELECT id, place_name, COALESCE(date, ''), COALESCE(cost, 0), COALESCE(notes, '') FROM food_visits"
+
+// GetFoodVisits lists a place's visits, newest first. Returns ErrNotFound if the place doesn't exist.
+func (dao *SQLiteDAO) GetFoodVisits(place string) ([]FoodVisit, error) {
+	var count int
+	err := dao.db.QueryRow("SELECT COUNT(*) FROM food_places WHERE user_uuid = ? AND name = ?", dao.user, place).Scan(&count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query food place: %w", err)
+	}
+	if count == 0 {
+		return nil, ErrNotFound
+	}
+
+	rows, err := dao.db.Query(sqliteFoodVisitSelect+" WHERE user_uuid = ? AND place_name = ? ORDER BY date DESC, id DESC", dao.user, place)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query food visits: %w", err)
+	}
+	defer rows.Close()
+
+	var visits []FoodVisit
+	for rows.Next() {
+		var visit FoodVisit
+		err = rows.Scan(&visit.ID, &visit.Place, &visit.Date, &visit.Cost, &visit.Notes)
+		if err != nil {
+			log.Printf("Failed to scan food visit row: %v", err)
+			continue
+		}
+		visits = append(visits, visit)
+	}
+
+	dishes, err := queryFoodDishes(dao.db, `SELECT d.visit_id, d.name, d.rating FROM food_visit_dishes d
+JOIN food_visits v ON v.id = d.visit_id WHERE v.user_uuid = ? AND v.place_name = ? ORDER BY d.visit_id, d.position`, dao.user, place)
+	if err != nil {
+		return nil, err
+	}
+	links, err := queryPeopleLinks(dao.db, `SELECT p.visit_id, p.person_id FROM food_visit_people p
+JOIN food_visits v ON v.id = p.visit_id WHERE v.user_uuid = ? AND v.place_name = ?`, dao.user, place)
+	if err != nil {
+		return nil, err
+	}
+	for i := range visits {
+		visits[i].Dishes = dishes[visits[i].ID]
+		visits[i].PeopleIDs = links[visits[i].ID]
+	}
+
+	return visits, nil
+}
+
+func (dao *SQLiteDAO) GetFoodVisit(place string, id int) (*FoodVisit, error) {
+	var visit FoodVisit
+	err := dao.db.QueryRow(sqliteFoodVisitSelect+" WHERE user_uuid = ? AND place_name = ? AND id = ?", dao.user, place, id).
+		Scan(&visit.ID, &visit.Place, &visit.Date, &visit.Cost, &visit.Notes)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query food visit: %w", err)
+	}
+
+	dishes, err := queryFoodDishes(dao.db, "SELECT visit_id, name, rating FROM food_visit_dishes WHERE visit_id = ? ORDER BY position", id)
+	if err != nil {
+		return nil, err
+	}
+	links, err := queryPeopleLinks(dao.db, "SELECT visit_id, person_id FROM food_visit_people WHERE visit_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	visit.Dishes = dishes[id]
+	visit.PeopleIDs = links[id]
+
+	return &visit, nil
+}
+
+// CreateFoodVisit logs a visit to visit.Place, taking the place off the wishlist.
+// Returns ErrNotFound if the place doesn't exist.
+func (dao *SQLiteDAO) CreateFoodVisit(visit FoodVisit) (int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE food_places SET wishlist = 0 WHERE user_uuid = ? AND name = ?", dao.user, visit.Place)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update food place: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return 0, err
+	}
+
+	var id int
+	insertQuery := "INSERT INTO food_visits (user_uuid, place_name, date, cost, notes) VALUES (?, ?, ?, ?, ?) RETURNING id"
+	err = tx.QueryRow(insertQuery, dao.user, visit.Place, visit.Date, visit.Cost, visit.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert food visit: %w", err)
+	}
+
+	err = dao.replaceFoodDishes(tx, id, visit.Dishes)
+	if err != nil {
+		return 0, err
+	}
+	err = dao.replacePeopleLinks(tx, "food_visit_people", "visit_id", id, visit.PeopleIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// UpdateFoodVisit replaces a visit and its dishes. Its people links are only replaced when
+// PeopleIDs is non-nil.
+func (dao *SQLiteDAO) UpdateFoodVisit(place string, id int, visit FoodVisit) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := "UPDATE food_visits SET date = ?, cost = ?, notes = ? WHERE user_uuid = ? AND place_name = ? AND id = ?"
+	result, err := tx.Exec(updateQuery, visit.Date, visit.Cost, visit.Notes, dao.user, place, id)
+	if err != nil {
+		return fmt.Errorf("failed to update food visit: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	err = dao.replaceFoodDishes(tx, id, visit.Dishes)
+	if err != nil {
+		return err
+	}
+	if visit.PeopleIDs != nil {
+		err = dao.replacePeopleLinks(tx, "food_visit_people", "visit_id", id, visit.PeopleIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (dao *SQLiteDAO) DeleteFoodVisit(place string, id int) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM food_visits WHERE user_uuid = ? AND place_name = ? AND id = ?", dao.user, place, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete food visit: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+	for _, table := range []string{"food_visit_dishes", "food_visit_people"} {
+		_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE visit_id = ?", table), id)
+		if err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// replaceFoodDishes rewrites a visit's dishes in the order given
+func (dao *SQLiteDAO) replaceFoodDishes(tx *sql.Tx, visitID int, dishes []Dish) error {
+	_, err := tx.Exec("DELETE FROM food_visit_dishes WHERE visit_id = ?", visitID)
+	if err != nil {
+		return fmt.Errorf("failed to clear dishes: %w", err)
+	}
+
+	for i, dish := range dishes {
+		_, err = tx.Exec("INSERT INTO food_visit_dishes (visit_id, position, name, rating) VALUES (?, ?, ?, ?)", visitID, i, dish.Name, dish.Rating)
+		if err != nil {
+			return fmt.Errorf("failed to insert dish: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// User methods
+
+// sqliteUserSelect reads the columns scanUser expects
+const sqliteUserSelect = `SELECT u.uuid, COALESCE(u.email, ''), COALESCE(u.created, ''), COALESCE(u.password_hash, ''), COALESCE(u.salt, '') FROM users u`
+
+// CreateUser adds an account, returning ErrConflict if the email is already registered
+func (dao *SQLiteDAO) CreateUser(user User) error {
+	return dao.createUser(user, false)
+}
+
+// CreateFirstUser adds an account only if there are none yet, returning ErrRegistrationClosed
+// otherwise. The check and the insert are one statement, so two registrations can't both be first.
+func (dao *SQLiteDAO) CreateFirstUser(user User) error {
+	return dao.createUser(user, true)
+}
+
+func (dao *SQLiteDAO) createUser(user User, onlyFirst bool) error {
+	insertQuery := "INSERT INTO users (uuid, email, password_hash, salt, created) VALUES (?, ?, ?, ?, ?)"
+	if onlyFirst {
+		insertQuery = "INSERT INTO users (uuid, email, password_hash, salt, created) SELECT ?, ?, ?, ?, ? WHERE NOT EXISTS (SELECT 1 FROM users)"
+	}
+	result, err := dao.db.Exec(insertQuery, user.UUID, user.Email, user.PasswordHash, user.Salt, time.Now().UTC())
+	if err != nil {
+		if isSQLiteConflict(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to insert user: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrRegistrationClosed
+	}
+	return nil
+}
+
+// ClaimUnownedRows gives everything saved before there were user accounts to the user with
+// the given UUID, returning how many rows that was
+func (dao *SQLiteDAO) ClaimUnownedRows(uuid string) (int, error) {
+	return claimUnownedRows(dao.db, uuid, func(n int) string { return "?" })
+}
+
+func (dao *SQLiteDAO) GetUserByEmail(email string) (*User, error) {
+	user, err := scanUser(dao.db.QueryRow(sqliteUserSelect+" WHERE u.email = ?", email))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return &user, nil
+}
+
+func (dao *SQLiteDAO) UpdateUserPassword(uuid, passwordHash, salt string) error {
+	result, err := dao.db.Exec("UPDATE users SET password_hash = ?, salt = ? WHERE uuid = ?", passwordHash, salt, uuid)
+	if err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return expectAffected(result)
+}
+
+// CreateSession signs a user in until expires, clearing out sessions that have already expired
+func (dao *SQLiteDAO) CreateSession(tokenHash, userUUID string, expires time.Time) error {
+	now := time.Now().UTC()
+	_, err := dao.db.Exec("DELETE FROM sessions WHERE expires <= ?", now)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+
+	_, err = dao.db.Exec("INSERT INTO sessions (token_hash, user_uuid, created, expires) VALUES (?, ?, ?, ?)",
+		tokenHash, userUUID, now, expires.UTC())
+	if err != nil {
+		return fmt.Errorf("failed to insert session: %w", err)
+	}
+	return nil
+}
+
+// GetSessionUser returns the user signed in with a session, or ErrNotFound if the session
+// doesn't exist or has expired
+func (dao *SQLiteDAO) GetSessionUser(tokenHash string) (*User, error) {
+	query := sqliteUserSelect + " JOIN sessions s ON s.user_uuid = u.uuid WHERE s.token_hash = ? AND s.expires > ?"
+	user, err := scanUser(dao.db.QueryRow(query, tokenHash, time.Now().UTC()))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query session: %w", err)
+	}
+	return &user, nil
+}
+
+func (dao *SQLiteDAO) DeleteSession(tokenHash string) error {
+	_, err := dao.db.Exec("DELETE FROM sessions WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// DeleteUserSessions signs a user out everywhere except the session keepTokenHash
+func (dao *SQLiteDAO) DeleteUserSessions(userUUID, keepTokenHash string) error {
+	_, err := dao.db.Exec("DELETE FROM sessions WHERE user_uuid = ? AND token_hash <> ?", userUUID, keepTokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to delete sessions: %w", err)
+	}
+	return nil
+}
+
+// Map methods
+func (dao *SQLiteDAO) GetMapPoints() ([]MapPoint, error) {
+	query := `SELECT 'food', COALESCE(name, ''), COALESCE(location, ''), '/food', latitude, longitude
+FROM food_places WHERE user_uuid = ? AND latitude IS NOT NULL AND longitude IS NOT NULL
+UNION ALL
+SELECT 'trip', p.name, COALESCE(t.title, ''), '/travel', p.latitude, p.longitude
+FROM trip_places p JOIN travel t ON t.id = p.trip_id WHERE t.user_uuid = ? AND p.latitude IS NOT NULL AND p.longitude IS NOT NULL
+UNION ALL
+SELECT 'photo', COALESCE(file_name, ''), COALESCE(taken, created, ''), '/api/photos/' || id, latitude, longitude
+FROM files WHERE user_uuid = ? AND latitude IS NOT NULL AND longitude IS NOT NULL`
+
+	rows, err := dao.db.Query(query, dao.user, dao.user, dao.user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query map points: %w", err)
+	}
+	defer rows.Close()
+
+	var points []MapPoint
+	for rows.Next() {
+		var point MapPoint
+		err = rows.Scan(&point.Type, &point.Name, &point.Description, &point.Link, &point.Latitude, &point.Longitude)
+		if err != nil {
+			log.Printf("Failed to scan map point row: %v", err)
+			continue
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// Timeline methods
+var sqliteTimelineSources = map[string]timelineSource{
+	"journal": {
+		Query:      "SELECT 'journal', COALESCE(created, ''), COALESCE(title, ''), substr(entry, 1, 200), '/entries#entry-' || id FROM journal_entries WHERE created IS NOT NULL",
+		DateColumn: "created",
+	},
+	"concert": {
+		Query:      "SELECT 'concert', COALESCE(date, ''), COALESCE(artists, ''), COALESCE(notes, ''), '/concerts#concert-' || date FROM concerts WHERE date IS NOT NULL",
+		DateColumn: "date",
+	},
+	"theater_movie": {
+		Query:      "SELECT 'theater_movie', COALESCE(date, ''), COALESCE(title, ''), COALESCE(notes, ''), '/theater-movies#theater-movie-' || id FROM theater_movies WHERE date IS NOT NULL",
+		DateColumn: "date",
+	},
+	"travel": {
+		Query:      "SELECT 'travel', COALESCE(start_date, dates), COALESCE(title, ''), COALESCE(notes, ''), '/travel#trip-' || id FROM travel WHERE COALESCE(start_date, dates) IS NOT NULL",
+		DateColumn: "COALESCE(start_date, dates)",
+	},
+	// Partial dates keep only the parts that are known, but are filtered as the first day they cover
+	"life_event": {
+		Query: `SELECT 'life_event', printf('%04d', year) || CASE WHEN month IS NULL THEN '' ELSE printf('-%02d', month) || CASE WHEN day IS NULL THEN '' ELSE printf('-%02d', day) END END,
+COALESCE(title, ''), COALESCE(notes, ''), '/timeline#life-event-' || id FROM life_events WHERE year IS NOT NULL`,
+		DateColumn: "printf('%04d-%02d-%02d', year, COALESCE(month, 1), COALESCE(day, 1))",
+	},
+	"book": {
+		Query:      "SELECT 'book', COALESCE(date_finished, ''), COALESCE(title, ''), COALESCE(author, ''), '/books#book-' || title FROM books WHERE date_finished IS NOT NULL",
+		DateColumn: "date_finished",
+	},
+	"memory": {
+		Query:      "SELECT 'memory', COALESCE(date, ''), 'Memory', COALESCE(notes, ''), '/memories#memory-' || id FROM random_memories WHERE date IS NOT NULL",
+		DateColumn: "date",
+	},
+}
+
+// GetTimeline merges the dated items of every selected category, newest first
+func (dao *SQLiteDAO) GetTimeline(query TimelineQuery) ([]TimelineItem, error) {
+	sqlQuery, args, err := buildTimelineQuery(sqliteTimelineSources, query, dao.user, func(int) string { return "?" })
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dao.db.Query(sqlQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query timeline: %w", err)
+	}
+	defer rows.Close()
+
+	var items []TimelineItem
+	for rows.Next() {
+		var item TimelineItem
+		err = rows.Scan(&item.Type, &item.Date, &item.Title, &item.Summary, &item.Link)
+		if err != nil {
+			log.Printf("Failed to scan timeline row: %v", err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetOnThisDay lists journal entries, concerts, life events, memories and photos from the
+// given calendar day in any year, newest first
+func (dao *SQLiteDAO) GetOnThisDay(month, day int) ([]TimelineItem, error) {
+	query := `SELECT 'journal', COALESCE(created, ''), COALESCE(title, ''), substr(entry, 1, 200), '/entries#entry-' || id FROM journal_entries WHERE user_uuid = ? AND strftime('%m-%d', created) = ?
+UNION ALL
+SELECT 'concert', COALESCE(date, ''), COALESCE(artists, ''), COALESCE(notes, ''), '/concerts#concert-' || date FROM concerts WHERE user_uuid = ? AND strftime('%m-%d', date) = ?
+UNION ALL
+SELECT 'life_event', printf('%04d-%02d-%02d', year, month, day), COALESCE(title, ''), COALESCE(notes, ''), '/timeline#life-event-' || id FROM life_events WHERE user_uuid = ? AND year IS NOT NULL AND month = ? AND day = ?
+UNION ALL
+SELECT 'memory', COALESCE(date, ''), 'Memory', COALESCE(notes, ''), '/memories#memory-' || id FROM random_memories WHERE user_uuid = ? AND strftime('%m-%d', date) = ?
+UNION ALL
+SELECT 'photo', COALESCE(taken, ''), COALESCE(file_name, ''), '', '/api/photos/' || id FROM files WHERE user_uuid = ? AND strftime('%m-%d', taken) = ?
+ORDER BY 2 DESC`
+
+	monthDay := fmt.Sprintf("%02d-%02d", month, day)
+	rows, err := dao.db.Query(query, dao.user, monthDay, dao.user, monthDay, dao.user, month, day, dao.user, monthDay, dao.user, monthDay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query on this day: %w", err)
+	}
+	defer rows.Close()
+
+	var items []TimelineItem
+	for rows.Next() {
+		var item TimelineItem
+		err = rows.Scan(&item.Type, &item.Date, &item.Title, &item.Summary, &item.Link)
+		if err != nil {
+			log.Printf("Failed to scan on this day row: %v", err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// sqlitePersonSelect reads the columns scanPerson expects, including when we were last in touch
+const sqlitePersonSelect = `SELECT id, COALESCE(first, ''), COALESCE(middle, ''), COALESCE(last, ''), COALESCE(address, ''),
+COALESCE(birth_day, 0), COALESCE(birth_month, 0), COALESCE(birth_year, 0), COALESCE(email, ''), COALESCE(category, ''), COALESCE(notes, ''),
+COALESCE(contact_every_days, 0), (SELECT COALESCE(MAX(i.date), '') FROM interactions i WHERE i.person_id = people.id)
+FROM people`
+
+// sqliteGiftIdeaSelect reads the columns queryGiftIdeas expects
+const sqliteGiftIdeaSelect = "SELECT g.person_id, g.idea, g.status, COALESCE(g.occasion, ''), g.price FROM gift_ideas g"
+
+// People methods
+func (dao *SQLiteDAO) GetAllPeople() ([]Person, error) {
+	rows, err := dao.db.Query(sqlitePersonSelect+" WHERE user_uuid = ? ORDER BY last, first", dao.user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query people: %w", err)
+	}
+	defer rows.Close()
+
+	var people []Person
+	for rows.Next() {
+		person, err := scanPerson(rows)
+		if err != nil {
+			log.Printf("Failed to scan person row: %v", err)
+			continue
+		}
+		people = append(people, person)
+	}
+
+	gifts, err := queryGiftIdeas(dao.db, sqliteGiftIdeaSelect+" JOIN people p ON p.id = g.person_id WHERE p.user_uuid = ? ORDER BY g.person_id, g.position", dao.user)
+	if err != nil {
+		return nil, err
+	}
+	for i := range people {
+		people[i].GiftIdeas = gifts[people[i].ID]
+	}
+
+	return people, nil
+}
+
+func (dao *SQLiteDAO) GetPerson(id int) (*Person, error) {
+	person, err := scanPerson(dao.db.QueryRow(sqlitePersonSelect+" WHERE user_uuid = ? AND id = ?", dao.user, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query person: %w", err)
+	}
+
+	gifts, err := queryGiftIdeas(dao.db, sqliteGiftIdeaSelect+" WHERE g.person_id = ? ORDER BY g.position", id)
+	if err != nil {
+		return nil, err
+	}
+	person.GiftIdeas = gifts[id]
+
+	return &person, nil
+}
+
+func (dao *SQLiteDAO) CreatePerson(person Person) (int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	id, err := dao.insertPerson(tx, person)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, tx.Commit()
+}
+
+// CreatePeople adds several people at once, returning their new ids in order. If any of
+// them fails none are added.
+func (dao *SQLiteDAO) CreatePeople(people []Person) ([]int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	ids := make([]int, len(people))
+	for i, person := range people {
+		ids[i], err = dao.insertPerson(tx, person)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, tx.Commit()
+}
+
+// insertPerson adds a person and their gift ideas in a transaction
+func (dao *SQLiteDAO) insertPerson(tx *sql.Tx, person Person) (int, error) {
+	insertQuery := `INSERT INTO people (user_uuid, first, middle, last, address, birth_day, birth_month, birth_year, email, category, notes, contact_every_days)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?) RETURNING id`
+	var id int
+	err := tx.QueryRow(insertQuery, dao.user, person.First, person.Middle, person.Last, person.Address, nullIfZero(person.BirthDay), nullIfZero(person.BirthMonth),
+		nullIfZero(person.BirthYear), person.Email, person.Category, person.Notes, nullIfZero(person.ContactEveryDays)).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert person: %w", err)
+	}
+
+	err = dao.replaceGiftIdeas(tx, id, person.GiftIdeas)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+// UpdatePerson replaces a person. Their gift ideas are only replaced when GiftIdeas is non-nil.
+func (dao *SQLiteDAO) UpdatePerson(id int, person Person) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `UPDATE people SET first = ?, middle = ?, last = ?, address = ?, birth_day = ?, birth_month = ?, birth_year = ?,
+email = ?, category = ?, notes = ?, contact_every_days = ? WHERE user_uuid = ? AND id = ?`
+	result, err := tx.Exec(updateQuery, person.First, person.Middle, person.Last, person.Address, nullIfZero(person.BirthDay), nullIfZero(person.BirthMonth),
+		nullIfZero(person.BirthYear), person.Email, person.Category, person.Notes, nullIfZero(person.ContactEveryDays), dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to update person: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	if person.GiftIdeas != nil {
+		err = dao.replaceGiftIdeas(tx, id, person.GiftIdeas)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeletePerson removes a person, unlinking them from everything they were part of
+func (dao *SQLiteDAO) DeletePerson(id int) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, table := range personTables {
+		_, err = tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE person_id = ?", table), id)
+		if err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
+	_, err = tx.Exec("DELETE FROM relationships WHERE person_id = ? OR related_id = ?", id, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete relationships: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM people WHERE user_uuid = ? AND id = ?", dao.user, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete person: %w", err)
+	}
+	if err = expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replaceGiftIdeas replaces a person's gift ideas, keeping them in the order given
+func (dao *SQLiteDAO) replaceGiftIdeas(tx *sql.Tx, personID int, gifts []GiftIdea) error {
+	_, err := tx.Exec("DELETE FROM gift_ideas WHERE person_id = ?", personID)
+	if err != nil {
+		return fmt.Errorf("failed to clear gift ideas: %w", err)
+	}
+
+	for i, gift := range gifts {
+		_, err = tx.Exec("INSERT INTO gift_ideas (person_id, position, idea, status, occasion, price) VALUES (?, ?, ?, ?, ?, ?)",
+			personID, i, gift.Idea, gift.Status, gift.Occasion, gift.Price)
+		if err != nil {
+			return fmt.Errorf("failed to insert gift idea: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (dao *SQLiteDAO) GetPersonTimeline(personID int) ([]TimelineItem, error) {
+	query := `SELECT 'concert', COALESCE(c.date, ''), COALESCE(c.artists, ''), COALESCE(c.notes, ''), '/concerts#concert-' || c.date
+FROM concerts c JOIN concert_people cp ON cp.user_uuid = c.user_uuid AND cp.concert_date = c.date WHERE c.user_uuid = ? AND cp.person_id = ?
+UNION ALL
+SELECT 'travel', COALESCE(t.start_date, t.dates, ''), COALESCE(t.title, ''), COALESCE(t.notes, ''), '/travel#trip-' || t.id
+FROM travel t JOIN travel_people tp ON tp.travel_id = t.id WHERE t.user_uuid = ? AND tp.person_id = ?
+UNION ALL
+SELECT 'theater_movie', COALESCE(m.date, ''), COALESCE(m.title, ''), COALESCE(m.notes, ''), '/theater-movies#theater-movie-' || m.id
+FROM theater_movies m JOIN theater_movie_people mp ON mp.theater_movie_id = m.id WHERE m.user_uuid = ? AND mp.person_id = ?
+UNION ALL
+SELECT 'memory', COALESCE(r.date, ''), 'Memory', COALESCE(r.notes, ''), '/memories#memory-' || r.id
+FROM random_memories r JOIN memory_people rp ON rp.memory_id = r.id WHERE r.user_uuid = ? AND rp.person_id = ?
+ORDER BY 2 DESC`
+
+	rows, err := dao.db.Query(query, dao.user, personID, dao.user, personID, dao.user, personID, dao.user, personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query person timeline: %w", err)
+	}
+	defer rows.Close()
+
+	items := []TimelineItem{}
+	for rows.Next() {
+		var item TimelineItem
+		err = rows.Scan(&item.Type, &item.Date, &item.Title, &item.Summary, &item.Link)
+		if err != nil {
+			log.Printf("Failed to scan timeline row: %v", err)
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// GetInteractions lists a person's interactions, newest first, returning ErrNotFound if
+// the person doesn't exist
+func (dao *SQLiteDAO) GetInteractions(personID int) ([]Interaction, error) {
+	var exists int
+	err := dao.db.QueryRow("SELECT COUNT(*) FROM people WHERE user_uuid = ? AND id = ?", dao.user, personID).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query person: %w", err)
+	}
+	if exists == 0 {
+		return nil, ErrNotFound
+	}
+
+	rows, err := dao.db.Query("SELECT id, person_id, COALESCE(date, ''), kind, COALESCE(notes, '') FROM interactions WHERE person_id = ? ORDER BY date DESC, id DESC", personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query interactions: %w", err)
+	}
+	defer rows.Close()
+
+	interactions := []Interaction{}
+	for rows.Next() {
+		interaction, err := scanInteraction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan interaction: %w", err)
+		}
+		interactions = append(interactions, interaction)
+	}
+
+	return interactions, rows.Err()
+}
+
+// CreateInteraction records an interaction, returning ErrNotFound if the person doesn't exist
+func (dao *SQLiteDAO) CreateInteraction(interaction Interaction) (int, error) {
+	var exists int
+	err := dao.db.QueryRow("SELECT COUNT(*) FROM people WHERE user_uuid = ? AND id = ?", dao.user, interaction.PersonID).Scan(&exists)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query person: %w", err)
+	}
+	if exists == 0 {
+		return 0, ErrNotFound
+	}
+
+	var id int
+	err = dao.db.QueryRow("INSERT INTO interactions (person_id, date, kind, notes) VALUES (?, ?, ?, ?) RETURNING id",
+		interaction.PersonID, interaction.Date, interaction.Kind, interaction.Notes).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert interaction: %w", err)
+	}
+	return id, nil
+}
+
+func (dao *SQLiteDAO) DeleteInteraction(id int) error {
+	result, err := dao.db.Exec("DELETE FROM interactions WHERE id = ? AND person_id IN (SELECT id FROM people WHERE user_uuid = ?)", id, dao.user)
+	if err != nil {
+		return fmt.Errorf("failed to delete interaction: %w", err)
+	}
+	return expectAffected(result)
+}
+
+func (dao *SQLiteDAO) GetRelationships() ([]Relationship, error) {
+	rows, err := dao.db.Query(`SELECT id, person_id, COALESCE(related_id, 0), type, COALESCE(notes, '') FROM relationships
+WHERE person_id IN (SELECT id FROM people WHERE user_uuid = ?) ORDER BY id`, dao.user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query relationships: %w", err)
+	}
+	defer rows.Close()
+
+	var relationships []Relationship
+	for rows.Next() {
+		var relationship Relationship
+		err = rows.Scan(&relationship.ID, &relationship.PersonID, &relationship.RelatedID, &relationship.Type, &relationship.Notes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan relationship: %w", err)
+		}
+		relationships = append(relationships, relationship)
+	}
+
+	return relationships, rows.Err()
+}
+
+// CreateRelationship records a relationship, returning ErrInvalidReference if either person
+// doesn't exist and ErrConflict if it has already been recorded
+func (dao *SQLiteDAO) CreateRelationship(relationship Relationship) (int, error) {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, personID := range []int{relationship.PersonID, relationship.RelatedID} {
+		if personID == 0 {
+			continue
+		}
+		var exists int
+		err = tx.QueryRow("SELECT COUNT(*) FROM people WHERE user_uuid = ? AND id = ?", dao.user, personID).Scan(&exists)
+		if err != nil {
+			return 0, fmt.Errorf("failed to query person: %w", err)
+		}
+		if exists == 0 {
+			return 0, ErrInvalidReference
+		}
+	}
+
+	var id int
+	err = tx.QueryRow("INSERT INTO relationships (person_id, related_id, type, notes) VALUES (?, ?, ?, ?) RETURNING id",
+		relationship.PersonID, nullIfZero(relationship.RelatedID), relationship.Type, relationship.Notes).Scan(&id)
+	if err != nil {
+		if isSQLiteConflict(err) {
+			return 0, ErrConflict
+		}
+		return 0, fmt.Errorf("failed to insert relationship: %w", err)
+	}
+
+	return id, tx.Commit()
+}
+
+func (dao *SQLiteDAO) DeleteRelationship(id int) error {
+	result, err := dao.db.Exec("DELETE FROM relationships WHERE id = ? AND person_id IN (SELECT id FROM people WHERE user_uuid = ?)", id, dao.user)
+	if err != nil {
+		return fmt.Errorf("failed to delete relationship: %w", err)
+	}
+	return expectAffected(result)
+}
+
+// sqlitePeopleLinkSources are the free text people columns and the join tables they link to
+var sqlitePeopleLinkSources = []peopleLinkSource{
+	{"concert", "SELECT COALESCE(date, ''), COALESCE(people_went_with, '') FROM concerts WHERE %s",
+		"INSERT OR IGNORE INTO concert_people (concert_date, person_id, user_uuid) SELECT ?, id, user_uuid FROM people WHERE id = ?"},
+	{"travel", "SELECT id, COALESCE(people_went_with, '') FROM travel WHERE %s",
+		"INSERT OR IGNORE INTO travel_people (travel_id, person_id) VALUES (?, ?)"},
+	{"theater_movie", "SELECT id, COALESCE(people_went_with, '') FROM theater_movies WHERE %s",
+		"INSERT OR IGNORE INTO theater_movie_people (theater_movie_id, person_id) VALUES (?, ?)"},
+	{"memory", "SELECT id, COALESCE(involved_people, '') FROM random_memories WHERE %s",
+		"INSERT OR IGNORE INTO memory_people (memory_id, person_id) VALUES (?, ?)"},
+}
+
+// LinkPeopleFromText matches the names in the free text people columns to people rows
+// and records them in the join tables. Existing links are left alone, so it is safe to rerun.
+func (dao *SQLiteDAO) LinkPeopleFromText() (*PeopleLinkReport, error) {
+	people, err := dao.GetAllPeople()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	report, err := linkPeople(tx, people, sqlitePeopleLinkSources, "user_uuid = ?", dao.user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit people links: %w", err)
+	}
+
+	return report, nil
+}
+
+// replacePeopleLinks points a row's entries in a people join table at personIDs,
+// returning ErrInvalidReference if any of them is not one of the user's people
+func (dao *SQLiteDAO) replacePeopleLinks(tx *sql.Tx, table, column string, id int, personIDs []int) error {
+	_, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table, column), id)
+	if err != nil {
+		return fmt.Errorf("failed to clear %s: %w", table, err)
+	}
+
+	for _, personID := range personIDs {
+		var exists int
+		err = tx.QueryRow("SELECT COUNT(*) FROM people WHERE user_uuid = ? AND id = ?", dao.user, personID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to query person: %w", err)
+		}
+		if exists == 0 {
+			return ErrInvalidReference
+		}
+
+		_, err = tx.Exec(fmt.Sprintf("INSERT OR IGNORE INTO %s (%s, person_id) VALUES (?, ?)", table, column), id, personID)
+		if err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// TV methods
+const sqliteTVShowSelect = "SELECT COALESCE(title, ''), COALESCE(notes, ''), COALESCE(seasons_watched, ''), COALESCE(childhood_show, 0) FROM tv_shows"
+
+// sqliteTVSeasonSelect returns the rows queryTVSeasons reads
+const sqliteTVSeasonSelect = `SELECT s.show_title, s.season, e.episode, e.title, COALESCE(e.watched_date, '')
+FROM tv_seasons s LEFT JOIN tv_episodes e ON e.user_uuid = s.user_uuid AND e.show_title = s.show_title AND e.season = s.season`
+
+func (dao *SQLiteDAO) GetAllTVShows() ([]TVShow, error) {
+	rows, err := dao.db.Query(sqliteTVShowSelect+" WHERE user_uuid = ? ORDER BY title", dao.user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query TV shows: %w", err)
+	}
+	defer rows.Close()
+
+	var tvShows []TVShow
+	for rows.Next() {
+		show, err := scanTVShow(rows)
+		if err != nil {
+			log.Printf("Failed to scan TV show row: %v", err)
+			continue
+		}
+		tvShows = append(tvShows, show)
+	}
+
+	seasons, err := queryTVSeasons(dao.db, sqliteTVSeasonSelect+" WHERE s.user_uuid = ? ORDER BY s.show_title, s.season, e.episode", dao.user)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tvShows {
+		tvShows[i].Seasons = seasons[tvShows[i].Title]
+		tvShows[i].NextEpisode = nextTVEpisode(tvShows[i].Seasons)
+	}
+
+	return tvShows, nil
+}
+
+func (dao *SQLiteDAO) GetTVShow(title string) (*TVShow, error) {
+	show, err := scanTVShow(dao.db.QueryRow(sqliteTVShowSelect+" WHERE user_uuid = ? AND title = ?", dao.user, title))
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query TV show: %w", err)
+	}
+
+	seasons, err := queryTVSeasons(dao.db, sqliteTVSeasonSelect+" WHERE s.user_uuid = ? AND s.show_title = ? ORDER BY s.season, e.episode", dao.user, title)
+	if err != nil {
+		return nil, err
+	}
+	show.Seasons = seasons[title]
+	show.NextEpisode = nextTVEpisode(show.Seasons)
+
+	return &show, nil
+}
+
+func (dao *SQLiteDAO) CreateTVShow(show TVShow) error {
+	insertQuery := "INSERT INTO tv_shows (user_uuid, title, notes, seasons_watched, childhood_show) VALUES (?, ?, ?, ?, ?)"
+	_, err := dao.db.Exec(insertQuery, dao.user, show.Title, show.Notes, nullIfEmpty(show.SeasonsWatched), show.ChildhoodShow)
+	if err != nil {
+		if isSQLiteConflict(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to insert TV show: %w", err)
+	}
+	return nil
+}
+
+// UpdateTVShow replaces a show's details, leaving its seasons alone. The title may change,
+// which fails with ErrConflict if another show already uses the new one; seasons and
+// episodes follow the rename through their foreign keys.
+func (dao *SQLiteDAO) UpdateTVShow(title string, show TVShow) error {
+	updateQuery := "UPDATE tv_shows SET title = ?, notes = ?, seasons_watched = ?, childhood_show = ? WHERE user_uuid = ? AND title = ?"
+	result, err := dao.db.Exec(updateQuery, show.Title, show.Notes, nullIfEmpty(show.SeasonsWatched), show.ChildhoodShow, dao.user, title)
+	if err != nil {
+		if isSQLiteConflict(err) {
+			return ErrConflict
+		}
+		return fmt.Errorf("failed to update TV show: %w", err)
+	}
+	return expectAffected(result)
+}
+
+func (dao *SQLiteDAO) DeleteTVShow(title string) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM tv_episodes WHERE user_uuid = ? AND show_title = ?", dao.user, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete TV episodes: %w", err)
+	}
+	_, err = tx.Exec("DELETE FROM tv_seasons WHERE user_uuid = ? AND show_title = ?", dao.user, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete TV seasons: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM tv_shows WHERE user_uuid = ? AND title = ?", dao.user, title)
+	if err != nil {
+		return fmt.Errorf("failed to delete TV show: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SaveTVSeason creates a season or replaces its episode list. Episodes are numbered from 1;
+// ones kept keep their watched dates and ones past the new end are removed.
+// Returns ErrNotFound if the show doesn't exist.
+func (dao *SQLiteDAO) SaveTVSeason(title string, season TVSeason) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	err = tx.QueryRow("SELECT COUNT(*) FROM tv_shows WHERE user_uuid = ? AND title = ?", dao.user, title).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to query TV show: %w", err)
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+
+	_, err = tx.Exec("INSERT INTO tv_seasons (user_uuid, show_title, season) VALUES (?, ?, ?) ON CONFLICT (user_uuid, show_title, season) DO NOTHING",
+		dao.user, title, season.Number)
+	if err != nil {
+		return fmt.Errorf("failed to insert TV season: %w", err)
+	}
+
+	upsertQuery := `INSERT INTO tv_episodes (user_uuid, show_title, season, episode, title) VALUES (?, ?, ?, ?, ?)
+ON CONFLICT (user_uuid, show_title, season, episode) DO UPDATE SET title = excluded.title`
+	for _, episode := range season.Episodes {
+		_, err = tx.Exec(upsertQuery, dao.user, title, season.Number, episode.Number, episode.Title)
+		if err != nil {
+			return fmt.Errorf("failed to save TV episode: %w", err)
+		}
+	}
+
+	_, err = tx.Exec("DELETE FROM tv_episodes WHERE user_uuid = ? AND show_title = ? AND season = ? AND episode > ?", dao.user, title, season.Number, len(season.Episodes))
+	if err != nil {
+		return fmt.Errorf("failed to delete TV episodes: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+func (dao *SQLiteDAO) DeleteTVSeason(title string, season int) error {
+	tx, err := dao.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec("DELETE FROM tv_episodes WHERE user_uuid = ? AND show_title = ? AND season = ?", dao.user, title, season)
+	if err != nil {
+		return fmt.Errorf("failed to delete TV episodes: %w", err)
+	}
+	result, err := tx.Exec("DELETE FROM tv_seasons WHERE user_uuid = ? AND show_title = ? AND season = ?", dao.user, title, season)
+	if err != nil {
+		return fmt.Errorf("failed to delete TV season: %w", err)
+	}
+	if err := expectAffected(result); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// MarkTVSeasonWatched marks every unwatched episode of a season as watched on date.
+// Episodes already watched keep their original date.
+func (dao *SQLiteDAO) MarkTVSeasonWatched(title string, season int, date string) error {
+	var count int
+	err := dao.db.QueryRow("SELECT COUNT(*) FROM tv_seasons WHERE user_uuid = ? AND show_title = ? AND season = ?", dao.user, title, season).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("failed to query TV season: %w", err)
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+
+	_, err = dao.db.Exec("UPDATE tv_episodes SET watched_date = ? WHERE user_uuid = ? AND show_title = ? AND season = ? AND watched_date IS NULL", date, dao.user, title, season)
+	if err != nil {
+		return fmt.Errorf("failed to mark TV season watched: %w", err)
+	}
+	return nil
+}
+
+// SetTVEpisodeWatched records when an episode was watched. An empty date marks it unwatched.
+func (dao *SQLiteDAO) SetTVEpisodeWatched(title string, season, episode int, date string) error {
+	updateQuery := "UPDATE tv_episodes SET watched_date = ? WHERE user_uuid = ? AND show_title = ? AND season = ? AND episode = ?"
+	result, err := dao.db.Exec(updateQuery, nullIfEmpty(date), dao.user, title, season, episode)
+	if err != nil {
+		return fmt.Errorf("failed to update TV episode: %w", err)
+	}
+	return expectAffected(result)
+}
+
+// Journal methods
+func (dao *SQLiteDAO) GetAllJournalEntries() ([]JournalEntry, error) {
+	rows, err := dao.db.Query("SELECT id, COALESCE(created, ''), COALESCE(title, ''), COALESCE(entry, ''), COALESCE(tags, ''), COALESCE(photos, '') FROM journal_entries WHERE user_uuid = ? ORDER BY created DESC", dao.user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query journal entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []JournalEntry
+	for rows.Next() {
+		var entry JournalEntry
+		err = rows.Scan(&entry.ID, &entry.Created, &entry.Title, &entry.Entry, &entry.Tags, &entry.Photos)
+		if err != nil {
+			log.Printf("Failed to scan journal entry row: %v", err)
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (dao *SQLiteDAO) CreateJournalEntry(title, entry, tags, photos string) error {
+	insertQuery := `INSERT INTO journal_entries (user_uuid, title, entry, tags, photos) VALUES (?, ?, ?, ?, ?)`
+	_, err := dao.db.Exec(insertQuery, dao.user, title, entry, tags, photos)
+	if err != nil {
+		return fmt.Errorf("failed to insert journal entry: %w", err)
+	}
+	return nil
+}
+
+// Photo methods
+func (dao *SQLiteDAO) CreatePhoto(fileName string, bytes []byte) (int, error) {
+	metadata := photoMetadata(bytes)
+	insertQuery := `INSERT INTO files (user_uuid, file_name, bytes, latitude, longitude, taken, metadata_version) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := dao.db.Exec(insertQuery, dao.user, fileName, bytes, metadata.Latitude, metadata.Longitude, photoTaken(metadata), photoMetadataVersion)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert photo: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return int(id), nil
+}
+
+func (dao *SQLiteDAO) GetPhotoByID(id int) (*Photo, error) {
+	var photo Photo
+	err := dao.db.QueryRow("SELECT id, COALESCE(file_name, ''), bytes, COALESCE(created, ''), COALESCE(taken, ''), latitude, longitude FROM files WHERE user_uuid = ? AND id = ?", dao.user, id).
+		Scan(&photo.ID, &photo.FileName, &photo.Bytes, &photo.Created, &photo.Taken, &photo.Latitude, &photo.Longitude)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("photo not found")
+		}
+		return nil, fmt.Errorf("failed to query photo: %w", err)
 	}
 
 	return &photo, nil
 }
+
+// isSQLiteConflict reports whether err is a primary key or unique constraint violation
+func isSQLiteConflict(err error) bool {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	return sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey || sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique
+}
@@ -0,0 +1,479 @@
+package daos
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	. "memories/model"
+)
+
+// openTestUsers returns DAOs for two users of a new SQLite database
+func openTestUsers(t *testing.T) (LifeJournalDAO, LifeJournalDAO) {
+	t.Helper()
+	db := InitSQLiteDB(filepath.Join(t.TempDir(), "test.sqlite"))
+	t.Cleanup(func() { db.Close() })
+
+	dao := NewSQLiteDAO(db)
+	for _, user := range []User{{UUID: "aaaaaaaa-0000-4000-8000-000000000000", Email: "a@example.com"},
+		{UUID: "bbbbbbbb-0000-4000-8000-000000000000", Email: "b@example.com"}} {
+		if err := dao.CreateUser(user); err != nil {
+			t.Fatalf("CreateUser(%s): %v", user.Email, err)
+		}
+	}
+	return dao.ForUser("aaaaaaaa-0000-4000-8000-000000000000"), dao.ForUser("bbbbbbbb-0000-4000-8000-000000000000")
+}
+
+// ownedRows are the IDs of what one user saved
+type ownedRows struct {
+	person, related, interaction, relationship  int
+	theaterMovie, trip, lifeEvent, memory       int
+	videoGame, readingSession, foodVisit, photo int
+}
+
+// saveEverything writes a row of each kind through dao, all dated June 1st
+func saveEverything(t *testing.T, dao LifeJournalDAO) ownedRows {
+	t.Helper()
+	var rows ownedRows
+	must := func(what string, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", what, err)
+		}
+	}
+	latitude, longitude := 52.37, 4.89
+
+	var err error
+	rows.person, err = dao.CreatePerson(Person{First: "Ada", Last: "Lovelace", BirthDay: 1, BirthMonth: 6})
+	must("CreatePerson", err)
+	rows.related, err = dao.CreatePerson(Person{First: "Charles", Last: "Babbage"})
+	must("CreatePerson", err)
+	rows.interaction, err = dao.CreateInteraction(Interaction{PersonID: rows.person, Date: "2020-06-01", Kind: "call"})
+	must("CreateInteraction", err)
+	rows.relationship, err = dao.CreateRelationship(Relationship{PersonID: rows.person, RelatedID: rows.related, Type: "friend"})
+	must("CreateRelationship", err)
+
+	must("CreateMovie", dao.CreateMovie(Movie{Title: "Alien", Tier: "S"}))
+	rows.theaterMovie, err = dao.CreateTheaterMovie(TheaterMovie{Title: "Alien", Date: "2020-06-01", PeopleIDs: []int{rows.person}})
+	must("CreateTheaterMovie", err)
+	rows.trip, err = dao.CreateTrip(Trip{Title: "Amsterdam", StartDate: "2020-06-01", EndDate: "2020-06-03",
+		Places: []TripPlace{{Name: "Amsterdam", Latitude: &latitude, Longitude: &longitude}}, PeopleIDs: []int{rows.person}})
+	must("CreateTrip", err)
+	rows.lifeEvent, err = dao.CreateLifeEvent(LifeEvent{Title: "Graduated", Year: 2020, Month: 6, Day: 1})
+	must("CreateLifeEvent", err)
+	rows.memory, err = dao.CreateMemory(Memory{Date: "2020-06-01", Notes: "Picnic", PeopleIDs: []int{rows.person}})
+	must("CreateMemory", err)
+	rows.videoGame, err = dao.CreateVideoGame(VideoGame{Title: "Tetris", Platform: "Game Boy", Status: "playing"})
+	must("CreateVideoGame", err)
+
+	must("CreateBook", dao.CreateBook(Book{Title: "Dune", Author: "Frank Herbert", Series: "Dune", Finished: true, DateFinished: "2020-06-01"}))
+	rows.readingSession, err = dao.CreateReadingSession(ReadingSession{BookTitle: "Dune", Date: "2020-06-01", PagesRead: 40})
+	must("CreateReadingSession", err)
+	must("SetReadingGoal", dao.SetReadingGoal(ReadingGoal{Year: 2020, Target: 12, Unit: "books"}))
+
+	_, err = dao.CreateFoodPlace(FoodPlace{Name: "Pancakes", Location: "Amsterdam"})
+	must("CreateFoodPlace", err)
+	must("SetFoodPlaceCoordinates", dao.SetFoodPlaceCoordinates("Pancakes", &latitude, &longitude))
+	rows.foodVisit, err = dao.CreateFoodVisit(FoodVisit{Place: "Pancakes", Date: "2020-06-01", PeopleIDs: []int{rows.person}})
+	must("CreateFoodVisit", err)
+
+	must("CreateTVShow", dao.CreateTVShow(TVShow{Title: "Lost"}))
+	must("SaveTVSeason", dao.SaveTVSeason("Lost", TVSeason{Number: 1, Episodes: []TVEpisode{{Number: 1, Title: "Pilot"}}}))
+	must("CreateJournalEntry", dao.CreateJournalEntry("Today", "Went outside", "", ""))
+	rows.photo, err = dao.CreatePhoto("photo.jpg", []byte("not really a jpeg"))
+	must("CreatePhoto", err)
+	return rows
+}
+
+func TestSQLiteUsersListOnlyTheirOwnRows(t *testing.T) {
+	a, b := openTestUsers(t)
+	rows := saveEverything(t, a)
+
+	// Sanity check that a can see what it saved, so the empty results below mean something
+	people, err := a.GetAllPeople()
+	if err != nil || len(people) != 2 {
+		t.Fatalf("a.GetAllPeople() = %d people, %v; want 2", len(people), err)
+	}
+	timeline, err := a.GetTimeline(TimelineQuery{Limit: 100})
+	if err != nil || len(timeline) == 0 {
+		t.Fatalf("a.GetTimeline() = %d items, %v; want some", len(timeline), err)
+	}
+
+	lists := []struct {
+		name string
+		list func() (int, error)
+	}{
+		{"GetAllConcerts", func() (int, error) { rows, err := b.GetAllConcerts(); return len(rows), err }},
+		{"GetAllMovies", func() (int, error) { rows, err := b.GetAllMovies(); return len(rows), err }},
+		{"GetTheaterMovies", func() (int, error) { rows, err := b.GetTheaterMovies(0, 0); return len(rows), err }},
+		{"GetTrips", func() (int, error) { rows, err := b.GetTrips(0, 0); return len(rows), err }},
+		{"GetLifeEvents", func() (int, error) { rows, err := b.GetLifeEvents(); return len(rows), err }},
+		{"GetMemories", func() (int, error) { rows, err := b.GetMemories("", ""); return len(rows), err }},
+		{"GetVideoGames", func() (int, error) { rows, err := b.GetVideoGames("", ""); return len(rows), err }},
+		{"GetBooks", func() (int, error) { rows, err := b.GetBooks(BookFilter{}); return len(rows), err }},
+		{"GetSeries", func() (int, error) { rows, err := b.GetSeries("Dune"); return len(rows), err }},
+		{"GetReadingSessions", func() (int, error) { rows, err := b.GetReadingSessions("Dune"); return len(rows), err }},
+		{"GetAllFoodPlaces", func() (int, error) { rows, err := b.GetAllFoodPlaces(); return len(rows), err }},
+		{"GetFoodPlacesByLocation", func() (int, error) { rows, err := b.GetFoodPlacesByLocation("Amsterdam"); return len(rows), err }},
+		{"GetFoodLocations", func() (int, error) { rows, err := b.GetFoodLocations(); return len(rows), err }},
+		{"GetAllPeople", func() (int, error) { rows, err := b.GetAllPeople(); return len(rows), err }},
+		{"GetPersonTimeline", func() (int, error) { items, err := b.GetPersonTimeline(rows.person); return len(items), err }},
+		{"GetRelationships", func() (int, error) { rows, err := b.GetRelationships(); return len(rows), err }},
+		{"GetAllTVShows", func() (int, error) { rows, err := b.GetAllTVShows(); return len(rows), err }},
+		{"GetAllJournalEntries", func() (int, error) { rows, err := b.GetAllJournalEntries(); return len(rows), err }},
+		{"GetMapPoints", func() (int, error) { rows, err := b.GetMapPoints(); return len(rows), err }},
+		{"GetTimeline", func() (int, error) { rows, err := b.GetTimeline(TimelineQuery{Limit: 100}); return len(rows), err }},
+		{"GetOnThisDay", func() (int, error) { rows, err := b.GetOnThisDay(6, 1); return len(rows), err }},
+	}
+	for _, tt := range lists {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := tt.list()
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if n != 0 {
+				t.Errorf("b sees %d of a's rows, want 0", n)
+			}
+		})
+	}
+}
+
+func TestSQLiteUsersCannotGetOrChangeOthersRows(t *testing.T) {
+	a, b := openTestUsers(t)
+	rows := saveEverything(t, a)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetTheaterMovie", func() error { _, err := b.GetTheaterMovie(rows.theaterMovie); return err }},
+		{"GetTrip", func() error { _, err := b.GetTrip(rows.trip); return err }},
+		{"GetLifeEvent", func() error { _, err := b.GetLifeEvent(rows.lifeEvent); return err }},
+		{"GetMemory", func() error { _, err := b.GetMemory(rows.memory); return err }},
+		{"GetVideoGame", func() error { _, err := b.GetVideoGame(rows.videoGame); return err }},
+		{"GetBook", func() error { _, err := b.GetBook("Dune"); return err }},
+		{"GetFoodVisits", func() error { _, err := b.GetFoodVisits("Pancakes"); return err }},
+		{"GetFoodVisit", func() error { _, err := b.GetFoodVisit("Pancakes", rows.foodVisit); return err }},
+		{"GetPerson", func() error { _, err := b.GetPerson(rows.person); return err }},
+		{"GetInteractions", func() error { _, err := b.GetInteractions(rows.person); return err }},
+		{"GetTVShow", func() error { _, err := b.GetTVShow("Lost"); return err }},
+
+		{"UpdateMovie", func() error { return b.UpdateMovie("Alien", Movie{Title: "Aliens", Tier: "A"}) }},
+		{"MoveMovie", func() error { return b.MoveMovie("Alien", "D", 0) }},
+		{"UpdateTheaterMovie", func() error {
+			return b.UpdateTheaterMovie(rows.theaterMovie, TheaterMovie{Title: "Aliens", Date: "2020-06-02"})
+		}},
+		{"UpdateTrip", func() error { return b.UpdateTrip(rows.trip, Trip{Title: "Paris", StartDate: "2020-06-01"}) }},
+		{"UpdateLifeEvent", func() error { return b.UpdateLifeEvent(rows.lifeEvent, LifeEvent{Title: "Moved", Year: 2021}) }},
+		{"UpdateMemory", func() error { return b.UpdateMemory(rows.memory, Memory{Date: "2020-06-02", Notes: "Rain"}) }},
+		{"UpdateVideoGame", func() error { return b.UpdateVideoGame(rows.videoGame, VideoGame{Title: "Doom", Status: "done"}) }},
+		{"UpdateBook", func() error { return b.UpdateBook("Dune", Book{Title: "Emma"}) }},
+		{"UpdateFoodVisit", func() error { return b.UpdateFoodVisit("Pancakes", rows.foodVisit, FoodVisit{Date: "2020-06-02"}) }},
+		{"SetFoodPlaceCoordinates", func() error { return b.SetFoodPlaceCoordinates("Pancakes", nil, nil) }},
+		{"UpdatePerson", func() error { return b.UpdatePerson(rows.person, Person{First: "Grace"}) }},
+		{"UpdateTVShow", func() error { return b.UpdateTVShow("Lost", TVShow{Title: "Found"}) }},
+		{"DeleteTVSeason", func() error { return b.DeleteTVSeason("Lost", 1) }},
+		{"SetTVEpisodeWatched", func() error { return b.SetTVEpisodeWatched("Lost", 1, 1, "2020-06-01") }},
+
+		{"DeleteMovie", func() error { return b.DeleteMovie("Alien") }},
+		{"DeleteTheaterMovie", func() error { return b.DeleteTheaterMovie(rows.theaterMovie) }},
+		{"DeleteTrip", func() error { return b.DeleteTrip(rows.trip) }},
+		{"DeleteLifeEvent", func() error { return b.DeleteLifeEvent(rows.lifeEvent) }},
+		{"DeleteMemory", func() error { return b.DeleteMemory(rows.memory) }},
+		{"DeleteVideoGame", func() error { return b.DeleteVideoGame(rows.videoGame) }},
+		{"DeleteBook", func() error { return b.DeleteBook("Dune") }},
+		{"DeleteReadingSession", func() error { return b.DeleteReadingSession(rows.readingSession) }},
+		{"DeleteFoodVisit", func() error { return b.DeleteFoodVisit("Pancakes", rows.foodVisit) }},
+		{"DeleteFoodPlace", func() error { return b.DeleteFoodPlace("Pancakes") }},
+		{"DeletePerson", func() error { return b.DeletePerson(rows.person) }},
+		{"DeleteInteraction", func() error { return b.DeleteInteraction(rows.interaction) }},
+		{"DeleteRelationship", func() error { return b.DeleteRelationship(rows.relationship) }},
+		{"DeleteTVShow", func() error { return b.DeleteTVShow("Lost") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, ErrNotFound) {
+				t.Errorf("err = %v, want ErrNotFound", err)
+			}
+		})
+	}
+
+	if photo, err := b.GetPhotoByID(rows.photo); err == nil {
+		t.Errorf("b.GetPhotoByID() = %q, want an error", photo.FileName)
+	}
+
+	// Nothing b tried should have touched a's rows
+	if again := saveEverything(t, b); again.person == 0 {
+		t.Fatal("b could not save its own rows")
+	}
+	book, err := a.GetBook("Dune")
+	if err != nil || book.Author != "Frank Herbert" {
+		t.Errorf("a.GetBook() = %+v, %v after b's changes", book, err)
+	}
+	people, err := a.GetAllPeople()
+	if err != nil || len(people) != 2 || people[0].First == "Grace" || people[1].First == "Grace" {
+		t.Errorf("a.GetAllPeople() = %+v, %v after b's changes", people, err)
+	}
+}
+
+func TestSQLiteUsersCannotLinkOthersPeople(t *testing.T) {
+	a, b := openTestUsers(t)
+	rows := saveEverything(t, a)
+	own, err := b.CreatePerson(Person{First: "Grace", Last: "Hopper"})
+	if err != nil {
+		t.Fatalf("CreatePerson: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want error
+	}{
+		{"CreateTheaterMovie", func() error {
+			_, err := b.CreateTheaterMovie(TheaterMovie{Title: "Heat", Date: "2021-01-01", PeopleIDs: []int{own, rows.person}})
+			return err
+		}, ErrInvalidReference},
+		{"CreateTrip", func() error {
+			_, err := b.CreateTrip(Trip{Title: "Rome", StartDate: "2021-01-01", PeopleIDs: []int{rows.person}})
+			return err
+		}, ErrInvalidReference},
+		{"CreateMemory", func() error {
+			_, err := b.CreateMemory(Memory{Date: "2021-01-01", Notes: "Snow", PeopleIDs: []int{rows.person}})
+			return err
+		}, ErrInvalidReference},
+		{"CreateFoodVisit", func() error {
+			if _, err := b.CreateFoodPlace(FoodPlace{Name: "Waffles"}); err != nil {
+				return err
+			}
+			_, err := b.CreateFoodVisit(FoodVisit{Place: "Waffles", Date: "2021-01-01", PeopleIDs: []int{rows.person}})
+			return err
+		}, ErrInvalidReference},
+		{"UpdateMemory", func() error {
+			id, err := b.CreateMemory(Memory{Date: "2021-01-01", Notes: "Hail"})
+			if err != nil {
+				return err
+			}
+			return b.UpdateMemory(id, Memory{Date: "2021-01-01", Notes: "Hail", PeopleIDs: []int{own, rows.person}})
+		}, ErrInvalidReference},
+		{"CreateRelationship", func() error {
+			_, err := b.CreateRelationship(Relationship{PersonID: own, RelatedID: rows.person, Type: "friend"})
+			return err
+		}, ErrInvalidReference},
+		// The person is the one the interaction is recorded under, so a's is just missing for b
+		{"CreateInteraction", func() error {
+			_, err := b.CreateInteraction(Interaction{PersonID: rows.person, Date: "2021-01-01", Kind: "call"})
+			return err
+		}, ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// None of b's attempts should have left a link to a's person behind
+	timeline, err := a.GetPersonTimeline(rows.person)
+	if err != nil {
+		t.Fatalf("a.GetPersonTimeline: %v", err)
+	}
+	for _, item := range timeline {
+		if item.Date == "2021-01-01" {
+			t.Errorf("a's person timeline has b's %s %q", item.Type, item.Title)
+		}
+	}
+	interactions, err := a.GetInteractions(rows.person)
+	if err != nil || len(interactions) != 1 {
+		t.Errorf("a.GetInteractions() = %d interactions, %v; want 1", len(interactions), err)
+	}
+}
+
+func TestSQLiteUpdateMovieKeepsTiersInOrder(t *testing.T) {
+	dao, _ := openTestUsers(t)
+	for _, movie := range []Movie{{Title: "Alien", Tier: "A"}, {Title: "Heat", Tier: "A"}, {Title: "Ran", Tier: "A"}, {Title: "Jaws", Tier: "S"}} {
+		if err := dao.CreateMovie(movie); err != nil {
+			t.Fatalf("CreateMovie(%s): %v", movie.Title, err)
+		}
+	}
+
+	err := dao.UpdateMovie("Alien", Movie{Title: "Alien", Tier: "S", Notes: "Better than I remembered"})
+	if err != nil {
+		t.Fatalf("UpdateMovie: %v", err)
+	}
+
+	for tier, want := range map[string][]string{"A": {"Heat", "Ran"}, "S": {"Jaws", "Alien"}} {
+		movies, err := dao.GetMoviesByTier(tier)
+		if err != nil {
+			t.Fatalf("GetMoviesByTier(%s): %v", tier, err)
+		}
+		if len(movies) != len(want) {
+			t.Fatalf("tier %s has %d movies, want %d", tier, len(movies), len(want))
+		}
+		for i, movie := range movies {
+			if movie.Title != want[i] || movie.Position != i {
+				t.Errorf("tier %s[%d] = %s at %d, want %s at %d", tier, i, movie.Title, movie.Position, want[i], i)
+			}
+		}
+	}
+}
+
+func TestSQLiteScoresFollowRenamesAndDeletes(t *testing.T) {
+	dao, _ := openTestUsers(t)
+	for _, title := range []string{"Alien", "Heat"} {
+		if err := dao.CreateMovie(Movie{Title: title, Tier: "A"}); err != nil {
+			t.Fatalf("CreateMovie(%s): %v", title, err)
+		}
+		if err := dao.CreateBook(Book{Title: title}); err != nil {
+			t.Fatalf("CreateBook(%s): %v", title, err)
+		}
+	}
+	for _, category := range []string{"movies", "books"} {
+		if err := dao.RecordComparison(category, "Alien", "Heat"); err != nil {
+			t.Fatalf("RecordComparison(%s): %v", category, err)
+		}
+	}
+
+	if err := dao.UpdateMovie("Alien", Movie{Title: "Aliens", Tier: "A"}); err != nil {
+		t.Fatalf("UpdateMovie: %v", err)
+	}
+	if err := dao.UpdateBook("Alien", Book{Title: "Aliens"}); err != nil {
+		t.Fatalf("UpdateBook: %v", err)
+	}
+	for _, category := range []string{"movies", "books"} {
+		ranking, err := dao.GetComparisonRanking(category)
+		if err != nil {
+			t.Fatalf("GetComparisonRanking(%s): %v", category, err)
+		}
+		if len(ranking) != 2 || ranking[0].Title != "Aliens" || ranking[0].Comparisons != 1 || ranking[0].Score <= ranking[1].Score {
+			t.Errorf("%s ranking after rename = %+v, want Aliens first with 1 comparison", category, ranking)
+		}
+	}
+
+	if err := dao.DeleteMovie("Aliens"); err != nil {
+		t.Fatalf("DeleteMovie: %v", err)
+	}
+	if err := dao.DeleteBook("Aliens"); err != nil {
+		t.Fatalf("DeleteBook: %v", err)
+	}
+	if err := dao.CreateMovie(Movie{Title: "Aliens", Tier: "A"}); err != nil {
+		t.Fatalf("CreateMovie(Aliens): %v", err)
+	}
+	if err := dao.CreateBook(Book{Title: "Aliens"}); err != nil {
+		t.Fatalf("CreateBook(Aliens): %v", err)
+	}
+	for _, category := range []string{"movies", "books"} {
+		ranking, err := dao.GetComparisonRanking(category)
+		if err != nil {
+			t.Fatalf("GetComparisonRanking(%s): %v", category, err)
+		}
+		for _, item := range ranking {
+			if item.Title == "Aliens" && item.Comparisons != 0 {
+				t.Errorf("%s: recreated Aliens has %d comparisons, want 0", category, item.Comparisons)
+			}
+		}
+	}
+}
+
+// Kinds of rows whose ID the database picks
+var generatedIDs = []struct {
+	table  string
+	create func(dao LifeJournalDAO) (int, error)
+	delete func(dao LifeJournalDAO, id int) error
+}{
+	{"theater_movies", func(dao LifeJournalDAO) (int, error) { return dao.CreateTheaterMovie(TheaterMovie{Title: "Alien"}) },
+		LifeJournalDAO.DeleteTheaterMovie},
+	{"travel", func(dao LifeJournalDAO) (int, error) { return dao.CreateTrip(Trip{Title: "Amsterdam"}) }, LifeJournalDAO.DeleteTrip},
+	{"life_events", func(dao LifeJournalDAO) (int, error) { return dao.CreateLifeEvent(LifeEvent{Title: "Graduated"}) },
+		LifeJournalDAO.DeleteLifeEvent},
+	{"random_memories", func(dao LifeJournalDAO) (int, error) { return dao.CreateMemory(Memory{Notes: "Picnic"}) },
+		LifeJournalDAO.DeleteMemory},
+	{"video_games", func(dao LifeJournalDAO) (int, error) { return dao.CreateVideoGame(VideoGame{Title: "Tetris"}) },
+		LifeJournalDAO.DeleteVideoGame},
+	{"people", func(dao LifeJournalDAO) (int, error) { return dao.CreatePerson(Person{First: "Ada"}) }, LifeJournalDAO.DeletePerson},
+}
+
+func TestSQLiteIDsAreNotReused(t *testing.T) {
+	a, b := openTestUsers(t)
+	for _, kind := range generatedIDs {
+		first, err := kind.create(a)
+		if err != nil {
+			t.Fatalf("%s: create: %v", kind.table, err)
+		}
+		if err := kind.delete(a, first); err != nil {
+			t.Fatalf("%s: delete: %v", kind.table, err)
+		}
+		second, err := kind.create(b)
+		if err != nil {
+			t.Fatalf("%s: create: %v", kind.table, err)
+		}
+		if second <= first {
+			t.Errorf("%s: id %d was given out after %d was deleted", kind.table, second, first)
+		}
+	}
+}
+
+func TestSQLiteTimelineLinksToItems(t *testing.T) {
+	dao, _ := openTestUsers(t)
+	rows := saveEverything(t, dao)
+	// Concerts are only ever imported, so there's no DAO method to save one
+	_, err := dao.(*SQLiteDAO).db.Exec("INSERT INTO concerts (user_uuid, date, artists) VALUES (?, '2020-06-01', 'Queen')", dao.(*SQLiteDAO).user)
+	if err != nil {
+		t.Fatalf("insert concert: %v", err)
+	}
+
+	want := map[string]string{
+		"journal":       "/entries#entry-1",
+		"concert":       "/concerts#concert-2020-06-01",
+		"theater_movie": fmt.Sprintf("/theater-movies#theater-movie-%d", rows.theaterMovie),
+		"travel":        fmt.Sprintf("/travel#trip-%d", rows.trip),
+		"life_event":    fmt.Sprintf("/timeline#life-event-%d", rows.lifeEvent),
+		"book":          "/books#book-Dune",
+		"memory":        fmt.Sprintf("/memories#memory-%d", rows.memory),
+	}
+	timeline, err := dao.GetTimeline(TimelineQuery{Limit: 100})
+	if err != nil {
+		t.Fatalf("GetTimeline: %v", err)
+	}
+	person, err := dao.GetPersonTimeline(rows.person)
+	if err != nil {
+		t.Fatalf("GetPersonTimeline: %v", err)
+	}
+	onThisDay, err := dao.GetOnThisDay(6, 1)
+	if err != nil {
+		t.Fatalf("GetOnThisDay: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, item := range append(append(timeline, person...), onThisDay...) {
+		if item.Type == "photo" {
+			continue
+		}
+		seen[item.Type] = true
+		if item.Link != want[item.Type] {
+			t.Errorf("%s %q links to %q, want %q", item.Type, item.Title, item.Link, want[item.Type])
+		}
+	}
+	for kind := range want {
+		if !seen[kind] {
+			t.Errorf("no %s on the timeline", kind)
+		}
+	}
+}
+
+func TestSQLiteVideoGamePlatformIgnoresCase(t *testing.T) {
+	dao, _ := openTestUsers(t)
+	for _, game := range []VideoGame{{Title: "Tetris", Platform: "Game Boy"}, {Title: "Doom", Platform: "PC"}} {
+		if _, err := dao.CreateVideoGame(game); err != nil {
+			t.Fatalf("CreateVideoGame(%s): %v", game.Title, err)
+		}
+	}
+
+	games, err := dao.GetVideoGames("", "game boy")
+	if err != nil {
+		t.Fatalf("GetVideoGames: %v", err)
+	}
+	if len(games) != 1 || games[0].Title != "Tetris" {
+		t.Errorf("GetVideoGames(game boy) = %+v, want Tetris", games)
+	}
+}
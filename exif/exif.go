@@ -0,0 +1,236 @@
+// Package exif reads the few EXIF fields the journal uses from JPEG photos
+package exif
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+// ErrNoExif is returned for files that are not JPEGs or carry no EXIF block
+var ErrNoExif = errors.New("no exif data")
+
+// Metadata holds the EXIF fields read from a photo. Fields the photo doesn't carry are nil.
+type Metadata struct {
+	Latitude  *float64
+	Longitude *float64
+	// Taken is when the photo was taken, in the camera's local time
+	Taken *time.Time
+}
+
+// Tags used from the TIFF structure
+const (
+	tagDateTime         = 0x0132
+	tagExifIFD          = 0x8769
+	tagDateTimeOriginal = 0x9003
+	tagGPSIFD           = 0x8825
+	tagGPSLatRef        = 0x0001
+	tagGPSLatitude      = 0x0002
+	tagGPSLonRef        = 0x0003
+	tagGPSLongitude     = 0x0004
+)
+
+// dateLayout is the format of EXIF date fields
+const dateLayout = "2006:01:02 15:04:05"
+
+// Field types used from the TIFF structure
+const (
+	typeASCII    = 2
+	typeLong     = 4
+	typeRational = 5
+)
+
+// Read extracts metadata from the EXIF block of a JPEG
+func Read(data []byte) (*Metadata, error) {
+	tiff, err := findTIFF(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, ErrNoExif
+	}
+	r := reader{data: tiff, order: order}
+
+	ifd0, ok := r.ifd(r.uint32(4))
+	if !ok {
+		return nil, ErrNoExif
+	}
+
+	metadata := &Metadata{}
+	if pointer, ok := ifd0[tagGPSIFD]; ok {
+		if gps, ok := r.ifd(r.long(pointer)); ok {
+			metadata.Latitude = r.coordinate(gps[tagGPSLatitude], gps[tagGPSLatRef], 'S')
+			metadata.Longitude = r.coordinate(gps[tagGPSLongitude], gps[tagGPSLonRef], 'W')
+			if metadata.Latitude == nil || metadata.Longitude == nil {
+				metadata.Latitude, metadata.Longitude = nil, nil
+			}
+		}
+	}
+
+	// DateTimeOriginal is when the shutter fired; DateTime is when the file last changed
+	// and only stands in for it when the camera left it out
+	taken := ""
+	if pointer, ok := ifd0[tagExifIFD]; ok {
+		if exifIFD, ok := r.ifd(r.long(pointer)); ok {
+			taken = r.ascii(exifIFD[tagDateTimeOriginal])
+		}
+	}
+	if taken == "" {
+		taken = r.ascii(ifd0[tagDateTime])
+	}
+	if t, err := time.Parse(dateLayout, taken); err == nil {
+		metadata.Taken = &t
+	}
+
+	return metadata, nil
+}
+
+// findTIFF walks the JPEG markers to the APP1 segment holding the EXIF TIFF structure
+func findTIFF(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, ErrNoExif
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, ErrNoExif
+		}
+		marker := data[pos+1]
+		// Start of scan: image data follows and there are no more metadata segments
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, ErrNoExif
+		}
+
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && len(segment) >= 14 && string(segment[:6]) == "Exif\x00\x00" {
+			return segment[6:], nil
+		}
+		pos = end
+	}
+
+	return nil, ErrNoExif
+}
+
+// entry is a single IFD field
+type entry struct {
+	typ   uint16
+	count uint32
+	value []byte // the 4 byte value/offset field
+}
+
+type reader struct {
+	data  []byte
+	order binary.ByteOrder
+}
+
+func (r reader) uint32(offset int) int {
+	if offset < 0 || offset+4 > len(r.data) {
+		return -1
+	}
+	return int(r.order.Uint32(r.data[offset:]))
+}
+
+// ifd reads the image file directory at offset into a map keyed by tag
+func (r reader) ifd(offset int) (map[uint16]entry, bool) {
+	if offset < 0 || offset+2 > len(r.data) {
+		return nil, false
+	}
+	count := int(r.order.Uint16(r.data[offset:]))
+	if offset+2+count*12 > len(r.data) {
+		return nil, false
+	}
+
+	entries := make(map[uint16]entry, count)
+	for i := 0; i < count; i++ {
+		start := offset + 2 + i*12
+		entries[r.order.Uint16(r.data[start:])] = entry{
+			typ:   r.order.Uint16(r.data[start+2:]),
+			count: r.order.Uint32(r.data[start+4:]),
+			value: r.data[start+8 : start+12],
+		}
+	}
+	return entries, true
+}
+
+// long returns a LONG field's value, or -1 if the field is missing or of another type
+func (r reader) long(e entry) int {
+	if e.typ != typeLong || e.count != 1 {
+		return -1
+	}
+	return int(r.order.Uint32(e.value))
+}
+
+// ascii returns an ASCII field's text without its trailing NUL
+func (r reader) ascii(e entry) string {
+	if e.typ != typeASCII || e.count == 0 {
+		return ""
+	}
+	var raw []byte
+	if e.count <= 4 {
+		raw = e.value[:e.count]
+	} else {
+		// Counted in 64 bits so a hostile offset and count can't overflow past the check
+		offset := uint64(r.order.Uint32(e.value))
+		if offset+uint64(e.count) > uint64(len(r.data)) {
+			return ""
+		}
+		raw = r.data[offset : offset+uint64(e.count)]
+	}
+	for i, b := range raw {
+		if b == 0 {
+			return string(raw[:i])
+		}
+	}
+	return string(raw)
+}
+
+// rationals returns a RATIONAL field's values
+func (r reader) rationals(e entry) []float64 {
+	if e.typ != typeRational || e.count == 0 {
+		return nil
+	}
+	offset := uint64(r.order.Uint32(e.value))
+	if offset+uint64(e.count)*8 > uint64(len(r.data)) {
+		return nil
+	}
+
+	values := make([]float64, e.count)
+	for i := range values {
+		start := offset + uint64(i)*8
+		numerator := r.order.Uint32(r.data[start:])
+		denominator := r.order.Uint32(r.data[start+4:])
+		if denominator == 0 {
+			return nil
+		}
+		values[i] = float64(numerator) / float64(denominator)
+	}
+	return values
+}
+
+// coordinate converts a degrees/minutes/seconds GPS field to signed decimal degrees
+func (r reader) coordinate(value, ref entry, negative byte) *float64 {
+	dms := r.rationals(value)
+	if len(dms) != 3 {
+		return nil
+	}
+
+	degrees := dms[0] + dms[1]/60 + dms[2]/3600
+	if direction := r.ascii(ref); direction != "" && direction[0] == negative {
+		degrees = -degrees
+	}
+	return &degrees
+}
@@ -0,0 +1,337 @@
+package exif
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+)
+
+// field is an IFD entry to lay out. Data of more than 4 bytes is stored after the IFDs.
+type field struct {
+	tag   uint16
+	typ   uint16
+	count uint32
+	data  []byte
+}
+
+// tiffBuilder lays out TIFF structures in one byte order
+type tiffBuilder struct {
+	order interface {
+		binary.ByteOrder
+		binary.AppendByteOrder
+	}
+}
+
+func (b tiffBuilder) ascii(tag uint16, text string) field {
+	data := append([]byte(text), 0)
+	return field{tag: tag, typ: typeASCII, count: uint32(len(data)), data: data}
+}
+
+// rationals takes numerator and denominator pairs
+func (b tiffBuilder) rationals(tag uint16, parts ...uint32) field {
+	var data []byte
+	for _, part := range parts {
+		data = b.order.AppendUint32(data, part)
+	}
+	return field{tag: tag, typ: typeRational, count: uint32(len(parts) / 2), data: data}
+}
+
+func (b tiffBuilder) long(tag uint16, value uint32) field {
+	return field{tag: tag, typ: typeLong, count: 1, data: b.order.AppendUint32(nil, value)}
+}
+
+// build writes IFD0 and, when they have fields, the Exif and GPS IFDs it points to
+func (b tiffBuilder) build(ifd0, exifIFD, gpsIFD []field) []byte {
+	ifdSize := func(fields []field) int { return 2 + len(fields)*12 + 4 }
+
+	ifd0 = append([]field{}, ifd0...)
+	if exifIFD != nil {
+		ifd0 = append(ifd0, b.long(tagExifIFD, 0))
+	}
+	if gpsIFD != nil {
+		ifd0 = append(ifd0, b.long(tagGPSIFD, 0))
+	}
+
+	exifOffset := 8 + ifdSize(ifd0)
+	gpsOffset := exifOffset
+	if exifIFD != nil {
+		gpsOffset += ifdSize(exifIFD)
+	}
+	dataOffset := gpsOffset
+	if gpsIFD != nil {
+		dataOffset += ifdSize(gpsIFD)
+	}
+	for i := range ifd0 {
+		switch ifd0[i].tag {
+		case tagExifIFD:
+			ifd0[i] = b.long(tagExifIFD, uint32(exifOffset))
+		case tagGPSIFD:
+			ifd0[i] = b.long(tagGPSIFD, uint32(gpsOffset))
+		}
+	}
+
+	out := []byte("II")
+	if b.order == binary.BigEndian {
+		out = []byte("MM")
+	}
+	out = b.order.AppendUint16(out, 42)
+	out = b.order.AppendUint32(out, 8)
+
+	var extra []byte
+	for _, fields := range [][]field{ifd0, exifIFD, gpsIFD} {
+		if fields == nil {
+			continue
+		}
+		out = b.order.AppendUint16(out, uint16(len(fields)))
+		for _, f := range fields {
+			out = b.order.AppendUint16(out, f.tag)
+			out = b.order.AppendUint16(out, f.typ)
+			out = b.order.AppendUint32(out, f.count)
+			if len(f.data) <= 4 {
+				out = append(out, f.data...)
+				out = append(out, make([]byte, 4-len(f.data))...)
+				continue
+			}
+			out = b.order.AppendUint32(out, uint32(dataOffset+len(extra)))
+			extra = append(extra, f.data...)
+		}
+		out = b.order.AppendUint32(out, 0)
+	}
+	return append(out, extra...)
+}
+
+// jpegWithExif wraps a TIFF structure in a JPEG's APP1 segment, after an APP0 segment
+// the reader has to skip
+func jpegWithExif(tiff []byte) []byte {
+	out := []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x06, 'J', 'F', 'I', 'F'}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	out = append(out, 0xFF, 0xE1)
+	out = binary.BigEndian.AppendUint16(out, uint16(len(segment)+2))
+	out = append(out, segment...)
+	return append(out, 0xFF, 0xDA, 0x00, 0x02, 0xFF, 0xD9)
+}
+
+var (
+	little = tiffBuilder{order: binary.LittleEndian}
+	big    = tiffBuilder{order: binary.BigEndian}
+)
+
+// gpsFields places a photo at 40°26'46" and 79°58'56" in the given directions
+func gpsFields(b tiffBuilder, latRef, lonRef string) []field {
+	return []field{
+		b.ascii(tagGPSLatRef, latRef),
+		b.rationals(tagGPSLatitude, 40, 1, 26, 1, 4600, 100),
+		b.ascii(tagGPSLonRef, lonRef),
+		b.rationals(tagGPSLongitude, 79, 1, 58, 1, 56, 1),
+	}
+}
+
+const (
+	wantLatitude  = 40 + 26.0/60 + 46.0/3600
+	wantLongitude = 79 + 58.0/60 + 56.0/3600
+)
+
+// withByte returns a copy of data with the byte at i replaced
+func withByte(data []byte, i int, value byte) []byte {
+	out := append([]byte{}, data...)
+	out[i] = value
+	return out
+}
+
+func TestRead(t *testing.T) {
+	valid := jpegWithExif(little.build(nil, []field{little.ascii(tagDateTimeOriginal, "2021:06:05 14:30:00")}, gpsFields(little, "N", "E")))
+	// The TIFF header starts after SOI, the APP0 segment, the APP1 marker and length, and "Exif\0\0"
+	tiffStart := 2 + 8 + 4 + 6
+
+	tests := []struct {
+		name      string
+		data      []byte
+		err       error
+		latitude  float64
+		longitude float64
+		hasGPS    bool
+		taken     string
+	}{
+		{
+			name:      "little endian",
+			data:      valid,
+			latitude:  wantLatitude,
+			longitude: wantLongitude,
+			hasGPS:    true,
+			taken:     "2021-06-05 14:30:00",
+		},
+		{
+			name: "big endian",
+			data: jpegWithExif(big.build(nil, []field{big.ascii(tagDateTimeOriginal, "2019:12:31 23:59:59")},
+				gpsFields(big, "N", "E"))),
+			latitude:  wantLatitude,
+			longitude: wantLongitude,
+			hasGPS:    true,
+			taken:     "2019-12-31 23:59:59",
+		},
+		{
+			name:      "south and west are negative",
+			data:      jpegWithExif(big.build(nil, nil, gpsFields(big, "S", "W"))),
+			latitude:  -wantLatitude,
+			longitude: -wantLongitude,
+			hasGPS:    true,
+		},
+		{
+			name: "DateTimeOriginal is preferred over DateTime",
+			data: jpegWithExif(little.build([]field{little.ascii(tagDateTime, "2024:01:01 00:00:00")},
+				[]field{little.ascii(tagDateTimeOriginal, "2020:02:03 04:05:06")}, nil)),
+			taken: "2020-02-03 04:05:06",
+		},
+		{
+			name:  "DateTime stands in without an Exif IFD",
+			data:  jpegWithExif(little.build([]field{little.ascii(tagDateTime, "2024:01:01 08:00:00")}, nil, nil)),
+			taken: "2024-01-01 08:00:00",
+		},
+		{
+			name: "DateTime stands in when DateTimeOriginal is missing",
+			data: jpegWithExif(little.build([]field{little.ascii(tagDateTime, "2024:01:01 08:00:00")},
+				[]field{little.ascii(tagExifIFD+1, "other")}, nil)),
+			taken: "2024-01-01 08:00:00",
+		},
+		{
+			name: "unparseable date",
+			data: jpegWithExif(little.build(nil, []field{little.ascii(tagDateTimeOriginal, "yesterday")}, nil)),
+		},
+		{
+			name: "latitude without longitude",
+			data: jpegWithExif(little.build(nil, nil, gpsFields(little, "N", "E")[:2])),
+		},
+		{
+			name: "zero denominator",
+			data: jpegWithExif(little.build(nil, nil, []field{
+				little.rationals(tagGPSLatitude, 40, 0, 26, 1, 46, 1),
+				little.rationals(tagGPSLongitude, 79, 1, 58, 1, 56, 1),
+			})),
+		},
+		{
+			name: "wrong number of coordinate parts",
+			data: jpegWithExif(little.build(nil, nil, []field{
+				little.rationals(tagGPSLatitude, 40, 1),
+				little.rationals(tagGPSLongitude, 79, 1),
+			})),
+		},
+		{
+			name: "oversized counts",
+			data: jpegWithExif(little.build(nil,
+				[]field{{tag: tagDateTimeOriginal, typ: typeASCII, count: math.MaxUint32, data: little.order.AppendUint32(nil, 8)}},
+				[]field{
+					{tag: tagGPSLatitude, typ: typeRational, count: math.MaxUint32, data: little.order.AppendUint32(nil, 8)},
+					{tag: tagGPSLongitude, typ: typeRational, count: 3, data: little.order.AppendUint32(nil, 8)},
+				})),
+		},
+		{
+			name: "oversized offsets",
+			data: jpegWithExif(little.build([]field{{tag: tagDateTime, typ: typeASCII, count: 20, data: little.order.AppendUint32(nil, math.MaxUint32)}},
+				nil,
+				[]field{
+					{tag: tagGPSLatitude, typ: typeRational, count: 3, data: little.order.AppendUint32(nil, math.MaxUint32)},
+					{tag: tagGPSLongitude, typ: typeRational, count: 3, data: little.order.AppendUint32(nil, math.MaxUint32-8)},
+				})),
+		},
+		{
+			name: "sub IFD pointers out of range",
+			data: jpegWithExif(little.build([]field{little.long(tagExifIFD, math.MaxUint32), little.long(tagGPSIFD, 1<<20)}, nil, nil)),
+		},
+		{
+			name: "sub IFD pointers of the wrong type",
+			data: jpegWithExif(little.build([]field{little.ascii(tagExifIFD, "abc"), little.ascii(tagGPSIFD, "abc")}, nil, nil)),
+		},
+		{
+			name: "IFD0 offset out of range",
+			data: jpegWithExif(little.order.AppendUint32([]byte("II\x2a\x00"), math.MaxUint32)),
+			err:  ErrNoExif,
+		},
+		{
+			name: "IFD0 entry count past the end",
+			data: jpegWithExif(append([]byte("II\x2a\x00\x08\x00\x00\x00"), 0xFF, 0xFF, 0, 0, 0, 0)),
+			err:  ErrNoExif,
+		},
+		{
+			name: "unknown byte order",
+			data: withByte(withByte(valid, tiffStart, 'X'), tiffStart+1, 'X'),
+			err:  ErrNoExif,
+		},
+		{
+			name: "APP1 length past the end",
+			data: withByte(valid, 2+8+2, 0xFF),
+			err:  ErrNoExif,
+		},
+		{
+			name: "segment length too short",
+			data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x01, 0xFF, 0xD9},
+			err:  ErrNoExif,
+		},
+		{
+			name: "no EXIF segment",
+			data: []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x06, 'J', 'F', 'I', 'F', 0xFF, 0xDA, 0x00, 0x02},
+			err:  ErrNoExif,
+		},
+		{
+			name: "not a JPEG",
+			data: []byte("\x89PNG\r\n\x1a\n"),
+			err:  ErrNoExif,
+		},
+		{
+			name: "empty",
+			err:  ErrNoExif,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := Read(tt.data)
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("got error %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !tt.hasGPS {
+				if metadata.Latitude != nil || metadata.Longitude != nil {
+					t.Errorf("got coordinates %v, %v, want none", metadata.Latitude, metadata.Longitude)
+				}
+			} else {
+				if metadata.Latitude == nil || math.Abs(*metadata.Latitude-tt.latitude) > 1e-9 {
+					t.Errorf("got latitude %v, want %v", metadata.Latitude, tt.latitude)
+				}
+				if metadata.Longitude == nil || math.Abs(*metadata.Longitude-tt.longitude) > 1e-9 {
+					t.Errorf("got longitude %v, want %v", metadata.Longitude, tt.longitude)
+				}
+			}
+
+			switch {
+			case tt.taken == "" && metadata.Taken != nil:
+				t.Errorf("got taken %v, want none", metadata.Taken)
+			case tt.taken != "" && metadata.Taken == nil:
+				t.Errorf("got no taken time, want %s", tt.taken)
+			case tt.taken != "" && metadata.Taken.Format("2006-01-02 15:04:05") != tt.taken:
+				t.Errorf("got taken %s, want %s", metadata.Taken.Format("2006-01-02 15:04:05"), tt.taken)
+			}
+		})
+	}
+}
+
+// TestReadTruncated cuts a valid photo off at every length, which must never panic
+func TestReadTruncated(t *testing.T) {
+	data := jpegWithExif(big.build([]field{big.ascii(tagDateTime, "2024:01:01 08:00:00")},
+		[]field{big.ascii(tagDateTimeOriginal, "2021:06:05 14:30:00")}, gpsFields(big, "S", "W")))
+	for i := range data {
+		metadata, err := Read(data[:i])
+		if err == nil && metadata == nil {
+			t.Fatalf("length %d: got neither metadata nor an error", i)
+		}
+		if err != nil && !errors.Is(err, ErrNoExif) {
+			t.Fatalf("length %d: got error %v, want ErrNoExif", i, err)
+		}
+	}
+}
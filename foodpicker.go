@@ -0,0 +1,89 @@
+package main
+
+import (
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	. "memories/model"
+)
+
+const (
+	// unratedFoodWeight stands in for the rating of places with no rated dishes, wishlist
+	// places included, so they still come up now and then
+	unratedFoodWeight = 5.0
+	// foodRecoveryDays is how long after a visit a place takes to get back to full weight
+	foodRecoveryDays = 30.0
+	// minFoodRecovery keeps places visited today in the running, just rarely
+	minFoodRecovery = 0.1
+)
+
+// foodPickFilter narrows the places the picker chooses from. Empty strings match anything.
+type foodPickFilter struct {
+	Type              string
+	Category          string
+	ExcludeRecentDays int
+}
+
+// foodWeight is a place's relative chance of being picked: its average rating, scaled down
+// for places visited in the last foodRecoveryDays days
+func foodWeight(place FoodPlace, now time.Time) float64 {
+	weight := unratedFoodWeight
+	if place.AverageRating != nil {
+		// +1 so places rated 0 aren't ruled out entirely
+		weight = *place.AverageRating + 1
+	}
+
+	if last, err := time.Parse("2006-01-02", place.LastVisit); err == nil {
+		days := now.Sub(last).Hours() / 24
+		recovery := min(1, max(minFoodRecovery, days/foodRecoveryDays))
+		weight *= recovery
+	}
+	return weight
+}
+
+// pickFoodPlace filters places and picks one at random, weighted by foodWeight.
+// Returns nil when no place matches the filter.
+func pickFoodPlace(places []FoodPlace, filter foodPickFilter, now time.Time) *FoodPick {
+	var candidates []FoodPlace
+	var weights []float64
+	total := 0.0
+	for _, place := range places {
+		if filter.Type != "" && !strings.EqualFold(place.Type, filter.Type) {
+			continue
+		}
+		if filter.Category != "" && !strings.EqualFold(place.Category, filter.Category) {
+			continue
+		}
+		if filter.ExcludeRecentDays > 0 && place.LastVisit != "" {
+			last, err := time.Parse("2006-01-02", place.LastVisit)
+			if err == nil && now.Sub(last) < time.Duration(filter.ExcludeRecentDays)*24*time.Hour {
+				continue
+			}
+		}
+
+		weight := foodWeight(place, now)
+		candidates = append(candidates, place)
+		weights = append(weights, weight)
+		total += weight
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	target := rand.Float64() * total
+	chosen := len(candidates) - 1
+	for i, weight := range weights {
+		if target < weight {
+			chosen = i
+			break
+		}
+		target -= weight
+	}
+
+	return &FoodPick{
+		Place:      candidates[chosen],
+		Chance:     weights[chosen] / total,
+		Candidates: len(candidates),
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	. "memories/model"
+)
+
+// GeoJSON (RFC 7946) shapes for the map export
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string            `json:"type"`
+	Geometry   geoJSONPoint      `json:"geometry"`
+	Properties map[string]string `json:"properties"`
+}
+
+type geoJSONPoint struct {
+	Type        string     `json:"type"`
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+// mapPointsToGeoJSON wraps map points in a feature collection. GeoJSON puts longitude first.
+func mapPointsToGeoJSON(points []MapPoint) geoJSONFeatureCollection {
+	collection := geoJSONFeatureCollection{Type: "FeatureCollection", Features: []geoJSONFeature{}}
+	for _, point := range points {
+		collection.Features = append(collection.Features, geoJSONFeature{
+			Type:     "Feature",
+			Geometry: geoJSONPoint{Type: "Point", Coordinates: [2]float64{point.Longitude, point.Latitude}},
+			Properties: map[string]string{
+				"type":        point.Type,
+				"name":        point.Name,
+				"description": point.Description,
+				"link":        point.Link,
+			},
+		})
+	}
+	return collection
+}
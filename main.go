@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -9,8 +10,9 @@ import (
 	. "memories/model"
 	"net/http"
 	"os"
-	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -45,9 +47,7 @@ func main() {
 	protocol := strings.ToLower(env("PROTOCOL"))
 	daoName := strings.ToLower(env("DAO"))
 
-	// Initialize Gin
 	gin.SetMode(gin.ReleaseMode) // Turn off debugging mode
-	r := gin.Default()           // Initialize Gin
 
 	//Ensure valid protocol env entry
 	if protocol != "http" && protocol != "https" {
@@ -91,82 +91,2837 @@ func main() {
 		defer closeDB()
 	}
 
+	// Everything saved before there were accounts belongs to DATA_OWNER_EMAIL
+	dataOwner := strings.ToLower(strings.TrimSpace(env("DATA_OWNER_EMAIL")))
+	if dataOwner != "" {
+		if err := claimDataOwnerRows(dao, dataOwner); err != nil {
+			log.Fatalf("Could not claim existing data for %s: %s", dataOwner, err)
+		}
+	}
+
+	secureCookies := protocol == "https"
+	allowRegistration := strings.ToLower(env("ALLOW_REGISTRATION")) == "true"
+	r := newRouter(dao, secureCookies, allowRegistration, dataOwner)
+
+	fmt.Printf("Listening for %v on port %v...\n", protocol, port) //Notifies that server is running on X port
+	if protocol == "http" {                                        //Start running the Gin server
+		err := r.Run(":" + port)
+		if err != nil {
+			fmt.Println(err)
+		}
+	} else if protocol == "https" {
+		err := r.RunTLS(":"+port, "./cert.pem", "./private.key")
+		if err != nil {
+			fmt.Println(err)
+		}
+	} else {
+		log.Fatal("Something went wrong starting the Gin server")
+	}
+
+}
+
+// newRouter sets up every page and API route over dao. Rows saved before there were accounts
+// go to dataOwner when they register.
+func newRouter(dao LifeJournalDAO, secureCookies, allowRegistration bool, dataOwner string) *gin.Engine {
+	r := gin.Default()
+
+	// Match routes on the escaped path, so a name with an encoded slash in it (like
+	// "Face%2FOff") stays in one :title segment
+	r.UseRawPath = true
+
+	// Everything but the login page and the login and registration APIs needs a signed in user
+	r.Use(requireAuth(dao))
+
+	// Login page
+	r.GET("/login", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/login.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Register a new user and sign them in. Only the first user, who must be DATA_OWNER_EMAIL
+	// if that's set, can register unless ALLOW_REGISTRATION is true. (JSON API)
+	r.POST("/api/auth/register", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var credentials Credentials
+		err = json.Unmarshal(data, &credentials)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+
+		if problem := validateCredentials(&credentials); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		// With registration closed, the only account is the data owner's when there is one
+		if !allowRegistration && dataOwner != "" && credentials.Email != dataOwner {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Registration is closed"})
+			return
+		}
+
+		// Hashing is slow on purpose, so don't let one address keep doing it
+		if !ipAttempts.add(c.ClientIP()) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many attempts, try again later"})
+			return
+		}
+
+		user := User{Email: credentials.Email}
+		user.UUID, err = newUUID()
+		if err == nil {
+			user.PasswordHash, user.Salt, err = hashPassword(credentials.Password)
+		}
+		if err != nil {
+			log.Println("Failed to prepare user:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not register"})
+			return
+		}
+
+		if allowRegistration {
+			err = dao.CreateUser(user)
+		} else {
+			err = dao.CreateFirstUser(user)
+		}
+		if errors.Is(err, ErrRegistrationClosed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Registration is closed"})
+			return
+		}
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "That email is already registered"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create user:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not register"})
+			return
+		}
+
+		if user.Email == dataOwner {
+			err = claimDataOwnerRows(dao, dataOwner)
+			if err != nil {
+				log.Println("Failed to claim existing data:", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Registered, but could not claim existing data"})
+				return
+			}
+		}
+
+		saved, err := dao.GetUserByEmail(user.Email)
+		if err == nil {
+			err = startSession(c, dao, saved, secureCookies)
+		}
+		if err != nil {
+			log.Println("Failed to start session:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Registered, but could not sign in"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, saved)
+	})
+
+	// Sign in with an email and password (JSON API)
+	r.POST("/api/auth/login", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var credentials Credentials
+		err = json.Unmarshal(data, &credentials)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+
+		user, err := authenticate(dao, c.ClientIP(), credentials.Email, credentials.Password)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+			return
+		}
+		if errors.Is(err, errTooManyAttempts) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many attempts, try again later"})
+			return
+		}
+		if err == nil {
+			err = startSession(c, dao, user, secureCookies)
+		}
+		if err != nil {
+			log.Println("Failed to sign in:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not sign in"})
+			return
+		}
+
+		c.JSON(http.StatusOK, user)
+	})
+
+	// Sign out of the current session (JSON API)
+	r.POST("/api/auth/logout", func(c *gin.Context) {
+		err := endSession(c, dao, secureCookies)
+		if err != nil {
+			log.Println("Failed to sign out:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not sign out"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	})
+
+	// Get the signed in user (JSON API)
+	r.GET("/api/auth/me", func(c *gin.Context) {
+		c.JSON(http.StatusOK, currentUser(c))
+	})
+
+	// Change the signed in user's password, signing out their other sessions (JSON API)
+	r.PUT("/api/auth/password", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var change PasswordChange
+		err = json.Unmarshal(data, &change)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+
+		user := currentUser(c)
+		if !checkPassword(change.CurrentPassword, user.PasswordHash, user.Salt) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Current password is wrong"})
+			return
+		}
+		if problem := validateCredentials(&Credentials{Email: user.Email, Password: change.NewPassword}); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		hash, salt, err := hashPassword(change.NewPassword)
+		if err == nil {
+			err = dao.UpdateUserPassword(user.UUID, hash, salt)
+		}
+		if err == nil {
+			err = dao.DeleteUserSessions(user.UUID, c.GetString(sessionKey))
+		}
+		if err != nil {
+			log.Println("Failed to change password:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not change password"})
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	})
+
 	// Home page
 	r.GET("/", func(c *gin.Context) {
 		html, _ := os.ReadFile("./assets/html/home.html")
 		c.Data(http.StatusOK, "text/html", html)
 	})
 
-	// Get all concerts (HTML page)
-	r.GET("/concerts", func(c *gin.Context) {
-		html, _ := os.ReadFile("./assets/html/concerts.html")
-		c.Data(http.StatusOK, "text/html", html)
+	// Get all concerts (HTML page)
+	r.GET("/concerts", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/concerts.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get all concerts (JSON API)
+	r.GET("/api/concerts", func(c *gin.Context) {
+		concerts, err := userDAO(c).GetAllConcerts()
+		if err != nil {
+			log.Printf("Could not get concerts: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get concerts"})
+			return
+		}
+
+		jsonData, err := json.Marshal(concerts)
+		if err != nil {
+			log.Printf("Could not marshal concerts: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get all movies (HTML page)
+	r.GET("/movies", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/movies.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get all movies (JSON API)
+	r.GET("/api/movies", func(c *gin.Context) {
+		movies, err := userDAO(c).GetAllMovies()
+		if err != nil {
+			log.Printf("Could not get movies: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get movies"})
+			return
+		}
+
+		jsonData, err := json.Marshal(movies)
+		if err != nil {
+			log.Printf("Could not marshal movies: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get all movies of the provided tier (JSON API)
+	r.GET("/api/movies/:tier", func(c *gin.Context) {
+		tier := strings.ToUpper(c.Param("tier"))
+		if !ValidMovieTier(tier) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tier"})
+			return
+		}
+
+		movies, err := userDAO(c).GetMoviesByTier(tier)
+		if err != nil {
+			log.Printf("Could not get movies: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get movies"})
+			return
+		}
+
+		jsonData, err := json.Marshal(movies)
+		if err != nil {
+			log.Printf("Could not marshal movies: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Create a movie (JSON API)
+	r.POST("/api/movies", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var movie Movie
+		err = json.Unmarshal(data, &movie)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateMovie(&movie); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).CreateMovie(movie)
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A movie with that title already exists"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create movie:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create movie"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, movie)
+	})
+
+	// Update a movie, including renaming it (JSON API)
+	r.PUT("/api/movies/:title", func(c *gin.Context) {
+		title := c.Param("title")
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var movie Movie
+		err = json.Unmarshal(data, &movie)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateMovie(&movie); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateMovie(title, movie)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+			return
+		}
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A movie with that title already exists"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update movie:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update movie"})
+			return
+		}
+
+		c.JSON(http.StatusOK, movie)
+	})
+
+	// Delete a movie (JSON API)
+	r.DELETE("/api/movies/:title", func(c *gin.Context) {
+		err := userDAO(c).DeleteMovie(c.Param("title"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete movie:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete movie"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Move a movie to a position within a tier, shifting the others (JSON API)
+	r.PUT("/api/movies/:title/tier", func(c *gin.Context) {
+		title := c.Param("title")
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var move struct {
+			Tier     string `json:"Tier"`
+			Position int    `json:"Position"`
+		}
+		err = json.Unmarshal(data, &move)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		move.Tier = strings.ToUpper(move.Tier)
+		if !ValidMovieTier(move.Tier) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tier"})
+			return
+		}
+
+		err = userDAO(c).MoveMovie(title, move.Tier, move.Position)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Movie not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to move movie:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not move movie"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Set the order of every movie in a tier from a JSON array of titles (JSON API)
+	r.PUT("/api/tiers/:tier/order", func(c *gin.Context) {
+		tier := strings.ToUpper(c.Param("tier"))
+		if !ValidMovieTier(tier) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tier"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var titles []string
+		err = json.Unmarshal(data, &titles)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+
+		err = userDAO(c).ReorderTier(tier, titles)
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Titles do not match the movies in this tier"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to reorder tier:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not reorder tier"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Pick movies or books against each other (HTML page)
+	r.GET("/compare", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/compare.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get a random pair of movies or books to compare (JSON API)
+	r.GET("/api/compare/:category/pair", func(c *gin.Context) {
+		category := strings.ToLower(c.Param("category"))
+		if !ValidComparisonCategory(category) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+			return
+		}
+
+		pair, err := userDAO(c).GetComparisonPair(category)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Not enough items to compare"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get comparison pair: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get comparison pair"})
+			return
+		}
+
+		c.JSON(http.StatusOK, pair)
+	})
+
+	// Record which of two items was liked more (JSON API)
+	r.POST("/api/compare/:category", func(c *gin.Context) {
+		category := strings.ToLower(c.Param("category"))
+		if !ValidComparisonCategory(category) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var outcome struct {
+			Winner string `json:"Winner"`
+			Loser  string `json:"Loser"`
+		}
+		err = json.Unmarshal(data, &outcome)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if outcome.Winner == "" || outcome.Loser == "" || outcome.Winner == outcome.Loser {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Winner and Loser must be two different items"})
+			return
+		}
+
+		err = userDAO(c).RecordComparison(category, outcome.Winner, outcome.Loser)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to record comparison:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not record comparison"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get movies or books ranked by comparison score (JSON API)
+	r.GET("/api/compare/:category/ranking", func(c *gin.Context) {
+		category := strings.ToLower(c.Param("category"))
+		if !ValidComparisonCategory(category) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+			return
+		}
+
+		ranking, err := userDAO(c).GetComparisonRanking(category)
+		if err != nil {
+			log.Printf("Could not get ranking: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get ranking"})
+			return
+		}
+
+		jsonData, err := json.Marshal(ranking)
+		if err != nil {
+			log.Printf("Could not marshal ranking: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get tier boundaries suggested by the comparison scores (JSON API)
+	r.GET("/api/compare/:category/tiers", func(c *gin.Context) {
+		category := strings.ToLower(c.Param("category"))
+		if !ValidComparisonCategory(category) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category"})
+			return
+		}
+
+		ranking, err := userDAO(c).GetComparisonRanking(category)
+		if err != nil {
+			log.Printf("Could not get ranking: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get ranking"})
+			return
+		}
+
+		c.JSON(http.StatusOK, SuggestTiers(ranking))
+	})
+
+	// Get all theater movies (HTML page)
+	r.GET("/theater-movies", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/theater_movies.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get theater movies, optionally for a year or range of years (JSON API)
+	r.GET("/api/theater-movies", func(c *gin.Context) {
+		fromYear, toYear, ok := yearRange(c)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+
+		movies, err := userDAO(c).GetTheaterMovies(fromYear, toYear)
+		if err != nil {
+			log.Printf("Could not get theater movies: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get theater movies"})
+			return
+		}
+
+		jsonData, err := json.Marshal(movies)
+		if err != nil {
+			log.Printf("Could not marshal theater movies: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get a single theater movie (JSON API)
+	r.GET("/api/theater-movies/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid theater movie ID"})
+			return
+		}
+
+		movie, err := userDAO(c).GetTheaterMovie(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Theater movie not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get theater movie: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get theater movie"})
+			return
+		}
+
+		c.JSON(http.StatusOK, movie)
+	})
+
+	// Create a theater movie (JSON API)
+	r.POST("/api/theater-movies", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var movie TheaterMovie
+		err = json.Unmarshal(data, &movie)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateTheaterMovie(&movie); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateTheaterMovie(movie)
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create theater movie:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create theater movie"})
+			return
+		}
+
+		// Read it back so the response includes the matching watched movie
+		saved, err := userDAO(c).GetTheaterMovie(id)
+		if err != nil {
+			log.Printf("Could not get theater movie: %v", err)
+			movie.ID = id
+			c.JSON(http.StatusCreated, movie)
+			return
+		}
+
+		c.JSON(http.StatusCreated, saved)
+	})
+
+	// Update a theater movie (JSON API)
+	r.PUT("/api/theater-movies/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid theater movie ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var movie TheaterMovie
+		err = json.Unmarshal(data, &movie)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateTheaterMovie(&movie); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateTheaterMovie(id, movie)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Theater movie not found"})
+			return
+		}
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update theater movie:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update theater movie"})
+			return
+		}
+
+		// Read it back so the response includes the matching watched movie
+		saved, err := userDAO(c).GetTheaterMovie(id)
+		if err != nil {
+			log.Printf("Could not get theater movie: %v", err)
+			movie.ID = id
+			c.JSON(http.StatusOK, movie)
+			return
+		}
+
+		c.JSON(http.StatusOK, saved)
+	})
+
+	// Delete a theater movie (JSON API)
+	r.DELETE("/api/theater-movies/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid theater movie ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteTheaterMovie(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Theater movie not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete theater movie:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete theater movie"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get all trips (HTML page)
+	r.GET("/travel", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/travel.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get trips, optionally for a year or range of years (JSON API)
+	r.GET("/api/travel", func(c *gin.Context) {
+		fromYear, toYear, ok := yearRange(c)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+
+		trips, err := userDAO(c).GetTrips(fromYear, toYear)
+		if err != nil {
+			log.Printf("Could not get trips: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get trips"})
+			return
+		}
+
+		jsonData, err := json.Marshal(trips)
+		if err != nil {
+			log.Printf("Could not marshal trips: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get a single trip (JSON API)
+	r.GET("/api/travel/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID"})
+			return
+		}
+
+		trip, err := userDAO(c).GetTrip(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Trip not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get trip: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get trip"})
+			return
+		}
+
+		c.JSON(http.StatusOK, trip)
+	})
+
+	// Create a trip (JSON API)
+	r.POST("/api/travel", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var trip Trip
+		err = json.Unmarshal(data, &trip)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateTrip(&trip); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateTrip(trip)
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create trip:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create trip"})
+			return
+		}
+
+		// Read it back so the response includes the stored places
+		saved, err := userDAO(c).GetTrip(id)
+		if err != nil {
+			log.Printf("Could not get trip: %v", err)
+			trip.ID = id
+			c.JSON(http.StatusCreated, trip)
+			return
+		}
+
+		c.JSON(http.StatusCreated, saved)
+	})
+
+	// Update a trip (JSON API)
+	r.PUT("/api/travel/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var trip Trip
+		err = json.Unmarshal(data, &trip)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateTrip(&trip); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateTrip(id, trip)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Trip not found"})
+			return
+		}
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update trip:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update trip"})
+			return
+		}
+
+		// Read it back so the response includes the stored places
+		saved, err := userDAO(c).GetTrip(id)
+		if err != nil {
+			log.Printf("Could not get trip: %v", err)
+			trip.ID = id
+			c.JSON(http.StatusOK, trip)
+			return
+		}
+
+		c.JSON(http.StatusOK, saved)
+	})
+
+	// Delete a trip (JSON API)
+	r.DELETE("/api/travel/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trip ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteTrip(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Trip not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete trip:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete trip"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get the life events timeline (HTML page)
+	r.GET("/timeline", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/timeline.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get all life events in chronological order (JSON API)
+	r.GET("/api/life-events", func(c *gin.Context) {
+		events, err := userDAO(c).GetLifeEvents()
+		if err != nil {
+			log.Printf("Could not get life events: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get life events"})
+			return
+		}
+
+		jsonData, err := json.Marshal(events)
+		if err != nil {
+			log.Printf("Could not marshal life events: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get a single life event (JSON API)
+	r.GET("/api/life-events/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid life event ID"})
+			return
+		}
+
+		event, err := userDAO(c).GetLifeEvent(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Life event not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get life event: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get life event"})
+			return
+		}
+
+		c.JSON(http.StatusOK, event)
+	})
+
+	// Create a life event (JSON API)
+	r.POST("/api/life-events", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var event LifeEvent
+		err = json.Unmarshal(data, &event)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateLifeEvent(&event); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateLifeEvent(event)
+		if err != nil {
+			log.Println("Failed to create life event:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create life event"})
+			return
+		}
+
+		event.ID = id
+		c.JSON(http.StatusCreated, event)
+	})
+
+	// Update a life event (JSON API)
+	r.PUT("/api/life-events/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid life event ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var event LifeEvent
+		err = json.Unmarshal(data, &event)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateLifeEvent(&event); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateLifeEvent(id, event)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Life event not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update life event:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update life event"})
+			return
+		}
+
+		event.ID = id
+		c.JSON(http.StatusOK, event)
+	})
+
+	// Delete a life event (JSON API)
+	r.DELETE("/api/life-events/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid life event ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteLifeEvent(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Life event not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete life event:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete life event"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get dated items from every category as one feed, newest first (JSON API)
+	r.GET("/api/timeline", func(c *gin.Context) {
+		query, problem := timelineQuery(c)
+		if problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		// Ask for one extra item to learn whether there is another page
+		page := TimelinePage{Limit: query.Limit, Offset: query.Offset}
+		query.Limit++
+		items, err := userDAO(c).GetTimeline(query)
+		if err != nil {
+			log.Printf("Could not get timeline: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get timeline"})
+			return
+		}
+		if len(items) > page.Limit {
+			items = items[:page.Limit]
+			page.HasMore = true
+		}
+		page.Items = items
+
+		jsonData, err := json.Marshal(page)
+		if err != nil {
+			log.Printf("Could not marshal timeline: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get what happened on a calendar day in past years, today unless date=MM-DD is given (JSON API)
+	r.GET("/api/on-this-day", func(c *gin.Context) {
+		date := c.DefaultQuery("date", time.Now().Format("01-02"))
+		month, day, ok := parseMonthDay(date)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be MM-DD"})
+			return
+		}
+
+		items, err := userDAO(c).GetOnThisDay(month, day)
+		if err != nil {
+			log.Printf("Could not get on this day: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get on this day"})
+			return
+		}
+
+		c.JSON(http.StatusOK, OnThisDay{Date: date, Years: groupByYear(items)})
+	})
+
+	// Get all memories (HTML page)
+	r.GET("/memories", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/memories.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get memories, optionally between from and to dates (JSON API)
+	r.GET("/api/memories", func(c *gin.Context) {
+		from, to := c.Query("from"), c.Query("to")
+		if !validDate(from) || !validDate(to) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from and to must be YYYY-MM-DD"})
+			return
+		}
+
+		memories, err := userDAO(c).GetMemories(from, to)
+		if err != nil {
+			log.Printf("Could not get memories: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get memories"})
+			return
+		}
+
+		jsonData, err := json.Marshal(memories)
+		if err != nil {
+			log.Printf("Could not marshal memories: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get a randomly chosen memory (JSON API)
+	r.GET("/api/memories/random", func(c *gin.Context) {
+		memory, err := userDAO(c).GetRandomMemory()
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No memories yet"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get random memory: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get memory"})
+			return
+		}
+
+		c.JSON(http.StatusOK, memory)
+	})
+
+	// Get a single memory (JSON API)
+	r.GET("/api/memories/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid memory ID"})
+			return
+		}
+
+		memory, err := userDAO(c).GetMemory(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Memory not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get memory: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get memory"})
+			return
+		}
+
+		c.JSON(http.StatusOK, memory)
+	})
+
+	// Create a memory (JSON API)
+	r.POST("/api/memories", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var memory Memory
+		err = json.Unmarshal(data, &memory)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateMemory(&memory); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateMemory(memory)
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create memory:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create memory"})
+			return
+		}
+
+		memory.ID = id
+		c.JSON(http.StatusCreated, memory)
+	})
+
+	// Update a memory (JSON API)
+	r.PUT("/api/memories/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid memory ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var memory Memory
+		err = json.Unmarshal(data, &memory)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateMemory(&memory); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateMemory(id, memory)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Memory not found"})
+			return
+		}
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update memory:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update memory"})
+			return
+		}
+
+		memory.ID = id
+		c.JSON(http.StatusOK, memory)
+	})
+
+	// Delete a memory (JSON API)
+	r.DELETE("/api/memories/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid memory ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteMemory(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Memory not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete memory:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete memory"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get all video games (HTML page)
+	r.GET("/games", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/games.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get video games, optionally filtered by status and platform (JSON API)
+	r.GET("/api/games", func(c *gin.Context) {
+		status := c.Query("status")
+		if status != "" && !ValidGameStatus(status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+
+		games, err := userDAO(c).GetVideoGames(status, c.Query("platform"))
+		if err != nil {
+			log.Printf("Could not get video games: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get video games"})
+			return
+		}
+
+		jsonData, err := json.Marshal(games)
+		if err != nil {
+			log.Printf("Could not marshal video games: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get a single video game (JSON API)
+	r.GET("/api/games/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video game ID"})
+			return
+		}
+
+		game, err := userDAO(c).GetVideoGame(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video game not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get video game: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get video game"})
+			return
+		}
+
+		c.JSON(http.StatusOK, game)
+	})
+
+	// Create a video game (JSON API)
+	r.POST("/api/games", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var game VideoGame
+		err = json.Unmarshal(data, &game)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateVideoGame(&game); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateVideoGame(game)
+		if err != nil {
+			log.Println("Failed to create video game:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create video game"})
+			return
+		}
+
+		game.ID = id
+		c.JSON(http.StatusCreated, game)
+	})
+
+	// Update a video game (JSON API)
+	r.PUT("/api/games/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video game ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var game VideoGame
+		err = json.Unmarshal(data, &game)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateVideoGame(&game); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateVideoGame(id, game)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video game not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update video game:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update video game"})
+			return
+		}
+
+		game.ID = id
+		c.JSON(http.StatusOK, game)
+	})
+
+	// Delete a video game (JSON API)
+	r.DELETE("/api/games/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid video game ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteVideoGame(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Video game not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete video game:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete video game"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get all books (HTML page)
+	r.GET("/books", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/books.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get books, optionally filtered by finished, owned and author (JSON API)
+	r.GET("/api/books", func(c *gin.Context) {
+		var filter BookFilter
+		var ok bool
+		if filter.Finished, ok = boolQuery(c, "finished"); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "finished must be true or false"})
+			return
+		}
+		if filter.Owned, ok = boolQuery(c, "owned"); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "owned must be true or false"})
+			return
+		}
+		filter.Author = strings.TrimSpace(c.Query("author"))
+
+		books, err := userDAO(c).GetBooks(filter)
+		if err != nil {
+			log.Printf("Could not get books: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get books"})
+			return
+		}
+
+		jsonData, err := json.Marshal(books)
+		if err != nil {
+			log.Printf("Could not marshal books: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get a single book (JSON API)
+	r.GET("/api/books/:title", func(c *gin.Context) {
+		book, err := userDAO(c).GetBook(c.Param("title"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get book: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get book"})
+			return
+		}
+
+		c.JSON(http.StatusOK, book)
+	})
+
+	// Create a book (JSON API)
+	r.POST("/api/books", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var book Book
+		err = json.Unmarshal(data, &book)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateBook(&book); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).CreateBook(book)
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A book with that title already exists"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create book:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create book"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, book)
+	})
+
+	// Update a book (JSON API)
+	r.PUT("/api/books/:title", func(c *gin.Context) {
+		title := c.Param("title")
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var book Book
+		err = json.Unmarshal(data, &book)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateBook(&book); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateBook(title, book)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			return
+		}
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A book with that title already exists"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update book:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update book"})
+			return
+		}
+
+		c.JSON(http.StatusOK, book)
+	})
+
+	// Delete a book (JSON API)
+	r.DELETE("/api/books/:title", func(c *gin.Context) {
+		err := userDAO(c).DeleteBook(c.Param("title"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete book:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete book"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get the books of a series in reading order with how many have been read (JSON API)
+	r.GET("/api/series/:name", func(c *gin.Context) {
+		name := c.Param("name")
+		books, err := userDAO(c).GetSeries(name)
+		if err != nil {
+			log.Printf("Could not get series: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get series"})
+			return
+		}
+		if len(books) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Series not found"})
+			return
+		}
+
+		series := BookSeries{Name: name, Books: books}
+		for _, book := range books {
+			if book.Finished {
+				series.Read++
+			} else {
+				series.Unread++
+			}
+		}
+
+		c.JSON(http.StatusOK, series)
+	})
+
+	// Get the reading sessions logged for a book, newest first (JSON API)
+	r.GET("/api/books/:title/sessions", func(c *gin.Context) {
+		title := c.Param("title")
+		if _, err := userDAO(c).GetBook(title); err != nil {
+			if errors.Is(err, ErrNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+				return
+			}
+			log.Printf("Could not get book: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get book"})
+			return
+		}
+
+		sessions, err := userDAO(c).GetReadingSessions(title)
+		if err != nil {
+			log.Printf("Could not get reading sessions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get reading sessions"})
+			return
+		}
+
+		jsonData, err := json.Marshal(sessions)
+		if err != nil {
+			log.Printf("Could not marshal reading sessions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Log a reading session for a book, moving its current page forward (JSON API)
+	r.POST("/api/books/:title/sessions", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var session ReadingSession
+		err = json.Unmarshal(data, &session)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		session.BookTitle = c.Param("title")
+		if problem := validateReadingSession(&session); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateReadingSession(session)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Book not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create reading session:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create reading session"})
+			return
+		}
+
+		session.ID = id
+		c.JSON(http.StatusCreated, session)
+	})
+
+	// Delete a reading session (JSON API)
+	r.DELETE("/api/reading/sessions/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid reading session ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteReadingSession(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reading session not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete reading session:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete reading session"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get the reading goal for a year (JSON API)
+	r.GET("/api/reading/goals/:year", func(c *gin.Context) {
+		var year int
+		_, err := fmt.Sscanf(c.Param("year"), "%d", &year)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+
+		goal, err := userDAO(c).GetReadingGoal(year)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No reading goal for that year"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get reading goal: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get reading goal"})
+			return
+		}
+
+		c.JSON(http.StatusOK, goal)
+	})
+
+	// Set the reading goal for a year, in books or pages (JSON API)
+	r.PUT("/api/reading/goals/:year", func(c *gin.Context) {
+		var year int
+		_, err := fmt.Sscanf(c.Param("year"), "%d", &year)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var goal ReadingGoal
+		err = json.Unmarshal(data, &goal)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		goal.Year = year
+		if problem := validateReadingGoal(&goal); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).SetReadingGoal(goal)
+		if err != nil {
+			log.Println("Failed to save reading goal:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save reading goal"})
+			return
+		}
+
+		c.JSON(http.StatusOK, goal)
+	})
+
+	// Get a year's reading stats: goal progress, pages per month and pace. Defaults to this year. (JSON API)
+	r.GET("/api/reading/stats", func(c *gin.Context) {
+		now := time.Now()
+		year := now.Year()
+		if value := c.Query("year"); value != "" {
+			_, err := fmt.Sscanf(value, "%d", &year)
+			if err != nil || year < 1 || year > 9999 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+				return
+			}
+		}
+
+		stats, err := userDAO(c).GetReadingStats(year)
+		if err != nil {
+			log.Printf("Could not get reading stats: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get reading stats"})
+			return
+		}
+		completeReadingStats(stats, now)
+
+		c.JSON(http.StatusOK, stats)
+	})
+
+	// Get all food places (HTML page)
+	r.GET("/food", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/food.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get all food places with their visit counts and ratings, optionally only the wishlist or only places we've been (JSON API)
+	r.GET("/api/food", func(c *gin.Context) {
+		wishlist, ok := boolQuery(c, "wishlist")
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "wishlist must be true or false"})
+			return
+		}
+
+		foodPlaces, err := userDAO(c).GetAllFoodPlaces()
+		if err != nil {
+			log.Printf("Could not get food: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food"})
+			return
+		}
+		if wishlist != nil {
+			foodPlaces = slices.DeleteFunc(foodPlaces, func(place FoodPlace) bool {
+				return place.Wishlist != *wishlist
+			})
+		}
+
+		jsonData, err := json.Marshal(foodPlaces)
+		if err != nil {
+			log.Printf("Could not marshal food places: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Add a food place, or a place to try with Wishlist set (JSON API)
+	r.POST("/api/food", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var place FoodPlace
+		err = json.Unmarshal(data, &place)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateFoodPlace(&place); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		place.Location, err = userDAO(c).CreateFoodPlace(place)
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A food place with that name already exists"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create food place:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create food place"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, place)
+	})
+
+	// Delete a food place along with its visits (JSON API)
+	r.DELETE("/api/food/:name", func(c *gin.Context) {
+		err := userDAO(c).DeleteFoodPlace(c.Param("name"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food place not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete food place:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete food place"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get all people (HTML page)
+	r.GET("/people", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/people.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
+
+	// Get all people (JSON API)
+	r.GET("/api/people", func(c *gin.Context) {
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+
+		jsonData, err := json.Marshal(people)
+		if err != nil {
+			log.Printf("Could not marshal people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get the birthdays in the next days days, 30 unless given, with the age each person turns (JSON API)
+	r.GET("/api/people/birthdays/upcoming", func(c *gin.Context) {
+		days := 30
+		if value := c.Query("days"); value != "" {
+			_, err := fmt.Sscanf(value, "%d", &days)
+			if err != nil || days < 1 || days > 366 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "days must be between 1 and 366"})
+				return
+			}
+		}
+
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+
+		c.JSON(http.StatusOK, upcomingBirthdays(people, time.Now(), days))
+	})
+
+	// Subscribe to everyone's birthdays and life event anniversaries (iCalendar feed)
+	r.GET("/calendar/birthdays.ics", func(c *gin.Context) {
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+		events, err := userDAO(c).GetLifeEvents()
+		if err != nil {
+			log.Printf("Could not get life events: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get life events"})
+			return
+		}
+
+		c.Header("Content-Disposition", `inline; filename="birthdays.ics"`)
+		c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(birthdayCalendar(people, events, time.Now())))
+	})
+
+	// Import people from a vCard file, sent as the body or as the "file" form field, skipping
+	// contacts that match someone by email or name (JSON API)
+	r.POST("/api/people/import", func(c *gin.Context) {
+		body := io.Reader(c.Request.Body)
+		if c.ContentType() == "multipart/form-data" {
+			file, err := c.FormFile("file")
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+				return
+			}
+			fileHandle, err := file.Open()
+			if err != nil {
+				log.Println("Failed to open file:", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open file"})
+				return
+			}
+			defer fileHandle.Close()
+			body = fileHandle
+		}
+		data, err := io.ReadAll(body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		contacts := parseVCards(string(data))
+		if len(contacts) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No vCards found"})
+			return
+		}
+
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+
+		// Everyone is added in one go so a failure partway through leaves nothing half imported
+		report := PeopleImportReport{Imported: []Person{}, Skipped: []SkippedContact{}}
+		for _, contact := range contacts {
+			name := personName(contact)
+			if problem := validatePerson(&contact); problem != "" {
+				report.Skipped = append(report.Skipped, SkippedContact{Name: name, Reason: problem})
+				continue
+			}
+			// Earlier contacts in the file count too, so a file can't add the same person twice
+			if id, reason := findDuplicatePerson(contact, people); reason != "" {
+				report.Skipped = append(report.Skipped, SkippedContact{Name: name, Reason: reason, PersonID: id})
+				continue
+			}
+
+			// Until they're saved, new people are told apart by negative placeholder ids
+			contact.ID = -len(report.Imported) - 1
+			report.Imported = append(report.Imported, contact)
+			people = append(people, contact)
+		}
+
+		ids, err := userDAO(c).CreatePeople(report.Imported)
+		if err != nil {
+			log.Println("Failed to import people:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not import people, so none were added"})
+			return
+		}
+		for i, id := range ids {
+			report.Imported[i].ID = id
+		}
+		for i, skipped := range report.Skipped {
+			if skipped.PersonID < 0 {
+				report.Skipped[i].PersonID = ids[-skipped.PersonID-1]
+			}
+		}
+
+		c.JSON(http.StatusOK, report)
+	})
+
+	// Export people as vCards, optionally only a category or a comma separated list of ids (vCard file)
+	r.GET("/api/people/export.vcf", func(c *gin.Context) {
+		var ids []int
+		if value := c.Query("ids"); value != "" {
+			for _, idStr := range strings.Split(value, ",") {
+				var id int
+				_, err := fmt.Sscanf(strings.TrimSpace(idStr), "%d", &id)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "ids must be a comma separated list of person IDs"})
+					return
+				}
+				ids = append(ids, id)
+			}
+		}
+		category := strings.TrimSpace(c.Query("category"))
+
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+		people = slices.DeleteFunc(people, func(person Person) bool {
+			if ids != nil && !slices.Contains(ids, person.ID) {
+				return true
+			}
+			return category != "" && !strings.EqualFold(person.Category, category)
+		})
+
+		c.Header("Content-Disposition", `attachment; filename="people.vcf"`)
+		c.Data(http.StatusOK, "text/vcard; charset=utf-8", []byte(peopleVCards(people)))
+	})
+
+	// Get a person with their gift ideas (JSON API)
+	r.GET("/api/people/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+
+		person, err := userDAO(c).GetPerson(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get person: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get person"})
+			return
+		}
+
+		c.JSON(http.StatusOK, person)
+	})
+
+	// Create a person (JSON API)
+	r.POST("/api/people", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var person Person
+		err = json.Unmarshal(data, &person)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validatePerson(&person); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreatePerson(person)
+		if err != nil {
+			log.Println("Failed to create person:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create person"})
+			return
+		}
+
+		person.ID = id
+		c.JSON(http.StatusCreated, person)
+	})
+
+	// Update a person. Their gift ideas are left alone when GiftIdeas is omitted. (JSON API)
+	r.PUT("/api/people/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var person Person
+		err = json.Unmarshal(data, &person)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validatePerson(&person); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdatePerson(id, person)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update person:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update person"})
+			return
+		}
+
+		// Read it back so the response includes gift ideas that were left alone
+		saved, err := userDAO(c).GetPerson(id)
+		if err != nil {
+			log.Printf("Could not get person: %v", err)
+			person.ID = id
+			c.JSON(http.StatusOK, person)
+			return
+		}
+
+		c.JSON(http.StatusOK, saved)
+	})
+
+	// Delete a person, unlinking them from concerts, trips, movies, memories and meals (JSON API)
+	r.DELETE("/api/people/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+
+		err = userDAO(c).DeletePerson(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete person:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete person"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get the people we haven't been in touch with as often as we'd like, most overdue first (JSON API)
+	r.GET("/api/people/overdue", func(c *gin.Context) {
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+
+		c.JSON(http.StatusOK, overduePeople(people, time.Now()))
+	})
+
+	// Get the calls, visits and messages with a person, newest first (JSON API)
+	r.GET("/api/people/:id/interactions", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+
+		interactions, err := userDAO(c).GetInteractions(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get interactions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get interactions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, interactions)
+	})
+
+	// Record being in touch with a person, today unless a Date is given (JSON API)
+	r.POST("/api/people/:id/interactions", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var interaction Interaction
+		err = json.Unmarshal(data, &interaction)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		interaction.PersonID = id
+		if problem := validateInteraction(&interaction, time.Now()); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		interaction.ID, err = userDAO(c).CreateInteraction(interaction)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create interaction:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create interaction"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, interaction)
+	})
+
+	// Delete an interaction (JSON API)
+	r.DELETE("/api/interactions/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interaction ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteInteraction(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Interaction not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete interaction:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete interaction"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get how a person is related to others and to us, from their side (JSON API)
+	r.GET("/api/people/:id/relationships", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+
+		_, err = userDAO(c).GetPerson(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get person: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get person"})
+			return
+		}
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+		relationships, err := userDAO(c).GetRelationships()
+		if err != nil {
+			log.Printf("Could not get relationships: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get relationships"})
+			return
+		}
+
+		c.JSON(http.StatusOK, personRelations(id, people, relationships))
+	})
+
+	// Get everyone and their relationships as a graph, optionally only a comma separated list of
+	// relationship types or family=true for a family tree (JSON API)
+	r.GET("/api/people/graph", func(c *gin.Context) {
+		var types []string
+		if value := c.Query("types"); value != "" {
+			for _, name := range strings.Split(value, ",") {
+				name = strings.ToLower(strings.TrimSpace(name))
+				if _, ok := relationshipType(name); !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown relationship type " + name})
+					return
+				}
+				types = append(types, name)
+			}
+		}
+		family, ok := boolQuery(c, "family")
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "family must be true or false"})
+			return
+		}
+		if family != nil && *family && types == nil {
+			for _, t := range RelationshipTypes {
+				if t.Family {
+					types = append(types, t.Name)
+				}
+			}
+		}
+
+		people, err := userDAO(c).GetAllPeople()
+		if err != nil {
+			log.Printf("Could not get people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			return
+		}
+		relationships, err := userDAO(c).GetRelationships()
+		if err != nil {
+			log.Printf("Could not get relationships: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get relationships"})
+			return
+		}
+
+		jsonData, err := json.Marshal(buildPeopleGraph(people, relationships, types))
+		if err != nil {
+			log.Printf("Could not marshal people graph: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get the kinds of relationship that can be recorded (JSON API)
+	r.GET("/api/relationships/types", func(c *gin.Context) {
+		c.JSON(http.StatusOK, RelationshipTypes)
+	})
+
+	// Record how a person is related to another person, or to us with RelatedID 0 (JSON API)
+	r.POST("/api/relationships", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var relationship Relationship
+		err = json.Unmarshal(data, &relationship)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateRelationship(&relationship); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateRelationship(relationship)
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "That relationship is already recorded"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create relationship:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create relationship"})
+			return
+		}
+
+		relationship.ID = id
+		c.JSON(http.StatusCreated, relationship)
+	})
+
+	// Delete a relationship (JSON API)
+	r.DELETE("/api/relationships/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid relationship ID"})
+			return
+		}
+
+		err = userDAO(c).DeleteRelationship(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Relationship not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete relationship:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete relationship"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Get every concert, trip, theater movie and memory shared with a person (JSON API)
+	r.GET("/api/people/:id/timeline", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid person ID"})
+			return
+		}
+
+		_, err = userDAO(c).GetPerson(id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Person not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get person: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get person"})
+			return
+		}
+		timeline, err := userDAO(c).GetPersonTimeline(id)
+		if err != nil {
+			log.Printf("Could not get person timeline: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get person timeline"})
+			return
+		}
+
+		jsonData, err := json.Marshal(timeline)
+		if err != nil {
+			log.Printf("Could not marshal person timeline: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Link the free text people columns to the people table and report unmatched names (JSON API)
+	r.POST("/api/people/links/migrate", func(c *gin.Context) {
+		report, err := userDAO(c).LinkPeopleFromText()
+		if err != nil {
+			log.Printf("Could not link people: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not link people"})
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	})
+
+	// Get the locations that have food places, with how many places each has (JSON API)
+	r.GET("/api/food/locations", func(c *gin.Context) {
+		locations, err := userDAO(c).GetFoodLocations()
+		if err != nil {
+			log.Printf("Could not get food locations: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food locations"})
+			return
+		}
+
+		jsonData, err := json.Marshal(locations)
+		if err != nil {
+			log.Printf("Could not marshal food locations: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Pick somewhere to eat at random, favouring higher rated places and ones we haven't been to lately (JSON API)
+	r.GET("/api/food/pick", func(c *gin.Context) {
+		filter := foodPickFilter{
+			Type:     strings.TrimSpace(c.Query("type")),
+			Category: strings.TrimSpace(c.Query("category")),
+		}
+		if value := c.Query("exclude_recent_days"); value != "" {
+			_, err := fmt.Sscanf(value, "%d", &filter.ExcludeRecentDays)
+			if err != nil || filter.ExcludeRecentDays < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "exclude_recent_days must be a whole number of days"})
+				return
+			}
+		}
+
+		var foodPlaces []FoodPlace
+		var err error
+		if location := strings.TrimSpace(c.Query("location")); location != "" {
+			foodPlaces, err = userDAO(c).GetFoodPlacesByLocation(location)
+		} else {
+			foodPlaces, err = userDAO(c).GetAllFoodPlaces()
+		}
+		if err != nil {
+			log.Printf("Could not get food: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food"})
+			return
+		}
+
+		pick := pickFoodPlace(foodPlaces, filter, time.Now())
+		if pick == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No food places match"})
+			return
+		}
+
+		c.JSON(http.StatusOK, pick)
+	})
+
+	// Get food places by location, matching the start of the location name or a whole region
+	// or country in any case. The segment is called name because gin needs one name for it
+	// across /api/food/:name and the visit routes below it. (JSON API)
+	r.GET("/api/food/:name", func(c *gin.Context) {
+		foodPlaces, err := userDAO(c).GetFoodPlacesByLocation(c.Param("name"))
+		if err != nil {
+			log.Printf("Could not get food: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food"})
+			return
+		}
+
+		jsonData, err := json.Marshal(foodPlaces)
+		if err != nil {
+			log.Printf("Could not marshal food places: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get the visits to a food place, newest first (JSON API)
+	r.GET("/api/food/:name/visits", func(c *gin.Context) {
+		visits, err := userDAO(c).GetFoodVisits(c.Param("name"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food place not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get food visits: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food visits"})
+			return
+		}
+
+		jsonData, err := json.Marshal(visits)
+		if err != nil {
+			log.Printf("Could not marshal food visits: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			return
+		}
+
+		gzipData := utils.GzipData(jsonData)
+
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", gzipData)
+	})
+
+	// Get a single visit to a food place (JSON API)
+	r.GET("/api/food/:name/visits/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visit ID"})
+			return
+		}
+
+		visit, err := userDAO(c).GetFoodVisit(c.Param("name"), id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Visit not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get food visit: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food visit"})
+			return
+		}
+
+		c.JSON(http.StatusOK, visit)
+	})
+
+	// Log a visit to a food place, taking it off the wishlist (JSON API)
+	r.POST("/api/food/:name/visits", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var visit FoodVisit
+		err = json.Unmarshal(data, &visit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		visit.Place = c.Param("name")
+		if problem := validateFoodVisit(&visit); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		id, err := userDAO(c).CreateFoodVisit(visit)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food place not found"})
+			return
+		}
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create food visit:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create food visit"})
+			return
+		}
+
+		visit.ID = id
+		c.JSON(http.StatusCreated, visit)
+	})
+
+	// Update a visit to a food place (JSON API)
+	r.PUT("/api/food/:name/visits/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visit ID"})
+			return
+		}
+
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+			return
+		}
+
+		var visit FoodVisit
+		err = json.Unmarshal(data, &visit)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		visit.ID = id
+		visit.Place = c.Param("name")
+		if problem := validateFoodVisit(&visit); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err = userDAO(c).UpdateFoodVisit(visit.Place, id, visit)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Visit not found"})
+			return
+		}
+		if errors.Is(err, ErrInvalidReference) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown person ID"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update food visit:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update food visit"})
+			return
+		}
+
+		c.JSON(http.StatusOK, visit)
 	})
 
-	// Get all concerts (JSON API)
-	r.GET("/api/concerts", func(c *gin.Context) {
-		concerts, err := dao.GetAllConcerts()
+	// Delete a visit to a food place (JSON API)
+	r.DELETE("/api/food/:name/visits/:id", func(c *gin.Context) {
+		idStr := c.Param("id")
+		var id int
+		_, err := fmt.Sscanf(idStr, "%d", &id)
 		if err != nil {
-			log.Printf("Could not get concerts: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get concerts"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid visit ID"})
 			return
 		}
 
-		jsonData, err := json.Marshal(concerts)
+		err = userDAO(c).DeleteFoodVisit(c.Param("name"), id)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Visit not found"})
+			return
+		}
 		if err != nil {
-			log.Printf("Could not marshal concerts: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			log.Println("Failed to delete food visit:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete food visit"})
 			return
 		}
 
-		gzipData := utils.GzipData(jsonData)
-
-		c.Header("Content-Encoding", "gzip")
-		c.Data(http.StatusOK, "application/json", gzipData)
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
 	})
 
-	// Get all movies (HTML page)
-	r.GET("/movies", func(c *gin.Context) {
-		html, _ := os.ReadFile("./assets/html/movies.html")
-		c.Data(http.StatusOK, "text/html", html)
-	})
+	// Set or clear the map coordinates of a food place (JSON API)
+	r.PUT("/api/food/:name/coordinates", func(c *gin.Context) {
+		name := c.Param("name")
 
-	// Get all movies (JSON API)
-	r.GET("/api/movies", func(c *gin.Context) {
-		movies, err := dao.GetAllMovies()
+		data, err := io.ReadAll(c.Request.Body)
 		if err != nil {
-			log.Printf("Could not get movies: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get movies"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 			return
 		}
 
-		jsonData, err := json.Marshal(movies)
+		var coordinates struct {
+			Latitude  *float64 `json:"Latitude"`
+			Longitude *float64 `json:"Longitude"`
+		}
+		err = json.Unmarshal(data, &coordinates)
 		if err != nil {
-			log.Printf("Could not marshal movies: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateCoordinates(coordinates.Latitude, coordinates.Longitude); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
 			return
 		}
 
-		gzipData := utils.GzipData(jsonData)
+		err = userDAO(c).SetFoodPlaceCoordinates(name, coordinates.Latitude, coordinates.Longitude)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Food place not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to set food place coordinates:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not set coordinates"})
+			return
+		}
 
-		c.Header("Content-Encoding", "gzip")
-		c.Data(http.StatusOK, "application/json", gzipData)
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
 	})
 
-	// Get all movies of the provided tier (JSON API)
-	r.GET("/api/movies/:tier", func(c *gin.Context) {
-		tier := strings.ToUpper(c.Param("tier"))
+	// Map of everywhere we've been (HTML page)
+	r.GET("/map", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/map.html")
+		c.Data(http.StatusOK, "text/html", html)
+	})
 
-		movies, err := dao.GetMoviesByTier(tier)
+	// Get every geolocated food place, trip stop and photo (GeoJSON API)
+	r.GET("/api/map.geojson", func(c *gin.Context) {
+		points, err := userDAO(c).GetMapPoints()
 		if err != nil {
-			log.Printf("Could not get movies: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get movies"})
+			log.Printf("Could not get map points: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get map points"})
 			return
 		}
 
-		jsonData, err := json.Marshal(movies)
+		jsonData, err := json.Marshal(mapPointsToGeoJSON(points))
 		if err != nil {
-			log.Printf("Could not marshal movies: %v", err)
+			log.Printf("Could not marshal map points: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
 			return
 		}
@@ -174,27 +2929,33 @@ func main() {
 		gzipData := utils.GzipData(jsonData)
 
 		c.Header("Content-Encoding", "gzip")
-		c.Data(http.StatusOK, "application/json", gzipData)
+		c.Data(http.StatusOK, "application/geo+json", gzipData)
 	})
 
-	// Get all books (HTML page)
-	r.GET("/books", func(c *gin.Context) {
-		html, _ := os.ReadFile("./assets/html/books.html")
+	r.GET("/favicon.ico", func(c *gin.Context) {
+		img, _ := os.ReadFile("./assets/images/img.png")
+
+		c.Data(http.StatusOK, "image/x-icon", img)
+	})
+
+	// Get all TV shows (HTML page)
+	r.GET("/tv", func(c *gin.Context) {
+		html, _ := os.ReadFile("./assets/html/tv.html")
 		c.Data(http.StatusOK, "text/html", html)
 	})
 
-	// Get all books (JSON API)
-	r.GET("/api/books", func(c *gin.Context) {
-		books, err := dao.GetAllBooks()
+	// Get all TV shows with their seasons and next episode (JSON API)
+	r.GET("/api/tv", func(c *gin.Context) {
+		tvShows, err := userDAO(c).GetAllTVShows()
 		if err != nil {
-			log.Printf("Could not get books: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get books"})
+			log.Printf("Could not get TV shows: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get TV shows"})
 			return
 		}
 
-		jsonData, err := json.Marshal(books)
+		jsonData, err := json.Marshal(tvShows)
 		if err != nil {
-			log.Printf("Could not marshal books: %v", err)
+			log.Printf("Could not marshal TV shows: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
 			return
 		}
@@ -205,119 +2966,288 @@ func main() {
 		c.Data(http.StatusOK, "application/json", gzipData)
 	})
 
-	// Get all food places (HTML page)
-	r.GET("/food", func(c *gin.Context) {
-		html, _ := os.ReadFile("./assets/html/food.html")
-		c.Data(http.StatusOK, "text/html", html)
+	// Get a single TV show (JSON API)
+	r.GET("/api/tv/:title", func(c *gin.Context) {
+		show, err := userDAO(c).GetTVShow(c.Param("title"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV show not found"})
+			return
+		}
+		if err != nil {
+			log.Printf("Could not get TV show: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get TV show"})
+			return
+		}
+
+		c.JSON(http.StatusOK, show)
 	})
 
-	// Get all food places (JSON API)
-	r.GET("/api/food", func(c *gin.Context) {
-		foodPlaces, err := dao.GetAllFoodPlaces()
+	// Get the next episode to watch of a TV show (JSON API)
+	r.GET("/api/tv/:title/next", func(c *gin.Context) {
+		show, err := userDAO(c).GetTVShow(c.Param("title"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV show not found"})
+			return
+		}
 		if err != nil {
-			log.Printf("Could not get food: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food"})
+			log.Printf("Could not get TV show: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get TV show"})
+			return
+		}
+		if show.NextEpisode == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No episodes left to watch"})
 			return
 		}
 
-		jsonData, err := json.Marshal(foodPlaces)
+		c.JSON(http.StatusOK, show.NextEpisode)
+	})
+
+	// Create a TV show (JSON API)
+	r.POST("/api/tv", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
 		if err != nil {
-			log.Printf("Could not marshal food places: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 			return
 		}
 
-		gzipData := utils.GzipData(jsonData)
+		var show TVShow
+		err = json.Unmarshal(data, &show)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateTVShow(&show); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
 
-		c.Header("Content-Encoding", "gzip")
-		c.Data(http.StatusOK, "application/json", gzipData)
-	})
+		err = userDAO(c).CreateTVShow(show)
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A TV show with that title already exists"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to create TV show:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not create TV show"})
+			return
+		}
 
-	// Get all people (HTML page)
-	r.GET("/people", func(c *gin.Context) {
-		html, _ := os.ReadFile("./assets/html/people.html")
-		c.Data(http.StatusOK, "text/html", html)
+		created, err := userDAO(c).GetTVShow(show.Title)
+		if err != nil {
+			log.Println("Failed to reload TV show:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get TV show"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, created)
 	})
 
-	// Get all people (JSON API)
-	r.GET("/api/people", func(c *gin.Context) {
-		people, err := dao.GetAllPeople()
+	// Update a TV show's details; seasons are saved separately (JSON API)
+	r.PUT("/api/tv/:title", func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
 		if err != nil {
-			log.Printf("Could not get people: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get people"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 			return
 		}
 
-		jsonData, err := json.Marshal(people)
+		var show TVShow
+		err = json.Unmarshal(data, &show)
 		if err != nil {
-			log.Printf("Could not marshal people: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		if problem := validateTVShow(&show); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
 			return
 		}
 
-		gzipData := utils.GzipData(jsonData)
+		err = userDAO(c).UpdateTVShow(c.Param("title"), show)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV show not found"})
+			return
+		}
+		if errors.Is(err, ErrConflict) {
+			c.JSON(http.StatusConflict, gin.H{"error": "A TV show with that title already exists"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to update TV show:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update TV show"})
+			return
+		}
 
-		c.Header("Content-Encoding", "gzip")
-		c.Data(http.StatusOK, "application/json", gzipData)
+		updated, err := userDAO(c).GetTVShow(show.Title)
+		if err != nil {
+			log.Println("Failed to reload TV show:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get TV show"})
+			return
+		}
+
+		c.JSON(http.StatusOK, updated)
+	})
+
+	// Delete a TV show along with its seasons and episodes (JSON API)
+	r.DELETE("/api/tv/:title", func(c *gin.Context) {
+		err := userDAO(c).DeleteTVShow(c.Param("title"))
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV show not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete TV show:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete TV show"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
 	})
 
-	// Get food places by location (JSON API)
-	r.GET("/api/food/:location", func(c *gin.Context) {
-		location := c.Param("location")
-		location = strings.ToLower(location)
+	// Create a season or replace its episode list, keeping watched dates (JSON API)
+	r.PUT("/api/tv/:title/seasons/:season", func(c *gin.Context) {
+		number, ok := positiveParam(c, "season")
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season number"})
+			return
+		}
 
-		foodPlaces, err := dao.GetFoodPlacesByLocation(location)
+		data, err := io.ReadAll(c.Request.Body)
 		if err != nil {
-			log.Printf("Could not get food: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get food"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
 			return
 		}
 
-		jsonData, err := json.Marshal(foodPlaces)
+		var season TVSeason
+		err = json.Unmarshal(data, &season)
 		if err != nil {
-			log.Printf("Could not marshal food places: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Error parsing body"})
+			return
+		}
+		season.Number = number
+		if problem := validateTVSeason(&season); problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
 			return
 		}
 
-		gzipData := utils.GzipData(jsonData)
+		title := c.Param("title")
+		err = userDAO(c).SaveTVSeason(title, season)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV show not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to save TV season:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save TV season"})
+			return
+		}
 
-		c.Header("Content-Encoding", "gzip")
-		c.Data(http.StatusOK, "application/json", gzipData)
+		show, err := userDAO(c).GetTVShow(title)
+		if err != nil {
+			log.Println("Failed to reload TV show:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get TV show"})
+			return
+		}
+
+		c.JSON(http.StatusOK, show)
 	})
 
-	r.GET("/favicon.ico", func(c *gin.Context) {
-		img, _ := os.ReadFile("./assets/images/img.png")
+	// Delete a season and its episodes (JSON API)
+	r.DELETE("/api/tv/:title/seasons/:season", func(c *gin.Context) {
+		number, ok := positiveParam(c, "season")
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season number"})
+			return
+		}
 
-		c.Data(http.StatusOK, "image/x-icon", img)
-	})
+		err := userDAO(c).DeleteTVSeason(c.Param("title"), number)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV season not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to delete TV season:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not delete TV season"})
+			return
+		}
 
-	// Get all TV shows (HTML page)
-	r.GET("/tv", func(c *gin.Context) {
-		html, _ := os.ReadFile("./assets/html/tv.html")
-		c.Data(http.StatusOK, "text/html", html)
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
 	})
 
-	// Get all TV shows (JSON API)
-	r.GET("/api/tv", func(c *gin.Context) {
-		tvShows, err := dao.GetAllTVShows()
+	// Mark every unwatched episode of a season watched, on the given date or today (JSON API)
+	r.PUT("/api/tv/:title/seasons/:season/watched", func(c *gin.Context) {
+		number, ok := positiveParam(c, "season")
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season number"})
+			return
+		}
+		date, problem := watchedDate(c)
+		if problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err := userDAO(c).MarkTVSeasonWatched(c.Param("title"), number, date)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV season not found"})
+			return
+		}
 		if err != nil {
-			log.Printf("Could not get TV shows: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get TV shows"})
+			log.Println("Failed to mark TV season watched:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not mark TV season watched"})
 			return
 		}
 
-		jsonData, err := json.Marshal(tvShows)
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
+
+	// Mark an episode watched, on the given date or today (JSON API)
+	r.PUT("/api/tv/:title/seasons/:season/episodes/:episode/watched", func(c *gin.Context) {
+		season, seasonOK := positiveParam(c, "season")
+		episode, episodeOK := positiveParam(c, "episode")
+		if !seasonOK || !episodeOK {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season or episode number"})
+			return
+		}
+		date, problem := watchedDate(c)
+		if problem != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": problem})
+			return
+		}
+
+		err := userDAO(c).SetTVEpisodeWatched(c.Param("title"), season, episode, date)
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV episode not found"})
+			return
+		}
 		if err != nil {
-			log.Printf("Could not marshal TV shows: %v", err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not encode data"})
+			log.Println("Failed to mark TV episode watched:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not mark TV episode watched"})
 			return
 		}
 
-		gzipData := utils.GzipData(jsonData)
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	})
 
-		c.Header("Content-Encoding", "gzip")
-		c.Data(http.StatusOK, "application/json", gzipData)
+	// Mark an episode unwatched (JSON API)
+	r.DELETE("/api/tv/:title/seasons/:season/episodes/:episode/watched", func(c *gin.Context) {
+		season, seasonOK := positiveParam(c, "season")
+		episode, episodeOK := positiveParam(c, "episode")
+		if !seasonOK || !episodeOK {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid season or episode number"})
+			return
+		}
+
+		err := userDAO(c).SetTVEpisodeWatched(c.Param("title"), season, episode, "")
+		if errors.Is(err, ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "TV episode not found"})
+			return
+		}
+		if err != nil {
+			log.Println("Failed to mark TV episode unwatched:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not mark TV episode unwatched"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Success"})
 	})
 
 	// Endpoint for file upload
@@ -339,16 +3269,8 @@ func main() {
 			return
 		}
 
+		// Photos are only kept in the DB, where they belong to the signed in user
 		for _, file := range files {
-			// Generate file path
-			filePath := filepath.Join("./uploads", file.Filename)
-
-			// Save file to disk
-			if err := c.SaveUploadedFile(file, filePath); err != nil {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
-				return
-			}
-
 			fileHandle, err := file.Open()
 			if err != nil {
 				log.Println("Failed to open file:", err)
@@ -356,6 +3278,7 @@ func main() {
 				return
 			}
 			data, err := io.ReadAll(fileHandle)
+			fileHandle.Close()
 			if err != nil {
 				log.Println("Failed to read file:", err)
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
@@ -363,7 +3286,7 @@ func main() {
 			}
 
 			// Use DAO to create photo and get ID
-			id, err := dao.CreatePhoto(file.Filename, data)
+			id, err := userDAO(c).CreatePhoto(file.Filename, data)
 			if err != nil {
 				log.Println("Failed to create photo:", err)
 				c.String(http.StatusInternalServerError, "Failed to insert data")
@@ -375,7 +3298,6 @@ func main() {
 
 		}
 
-		fmt.Println(fileIdsJson)
 		c.Data(http.StatusOK, "text/plain", fileIdsJson)
 	})
 
@@ -401,7 +3323,7 @@ func main() {
 		}
 
 		// Use DAO to create journal entry
-		err = dao.CreateJournalEntry(e.Title, e.Entry, e.Tags, e.Photos)
+		err = userDAO(c).CreateJournalEntry(e.Title, e.Entry, e.Tags, e.Photos)
 		if err != nil {
 			log.Println("Failed to create journal entry:", err)
 			c.String(http.StatusInternalServerError, "Failed to insert data")
@@ -413,7 +3335,7 @@ func main() {
 	})
 
 	r.GET("/api/journal", func(c *gin.Context) {
-		entries, err := dao.GetAllJournalEntries()
+		entries, err := userDAO(c).GetAllJournalEntries()
 		if err != nil {
 			log.Printf("Could not get journal entries: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not get journal entries"})
@@ -442,7 +3364,7 @@ func main() {
 			return
 		}
 
-		photo, err := dao.GetPhotoByID(id)
+		photo, err := userDAO(c).GetPhotoByID(id)
 		if err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Photo not found"})
 			return
@@ -473,21 +3395,5 @@ func main() {
 		c.Data(http.StatusOK, "text/css", css)
 	})
 
-	r.Static("/uploads", "./uploads")
-
-	fmt.Printf("Listening for %v on port %v...\n", protocol, port) //Notifies that server is running on X port
-	if protocol == "http" {                                        //Start running the Gin server
-		err := r.Run(":" + port)
-		if err != nil {
-			fmt.Println(err)
-		}
-	} else if protocol == "https" {
-		err := r.RunTLS(":"+port, "./cert.pem", "./private.key")
-		if err != nil {
-			fmt.Println(err)
-		}
-	} else {
-		log.Fatal("Something went wrong starting the Gin server")
-	}
-
+	return r
 }
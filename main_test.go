@@ -0,0 +1,140 @@
+package main
+
+import (
+	"io"
+	"memories/daos"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+	os.Exit(m.Run())
+}
+
+// testServer is the app over a new SQLite database, signed in as its only user
+type testServer struct {
+	t       *testing.T
+	router  *gin.Engine
+	cookies []*http.Cookie
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	db := daos.InitSQLiteDB(filepath.Join(t.TempDir(), "test.sqlite"))
+	t.Cleanup(func() { db.Close() })
+
+	server := &testServer{t: t, router: newRouter(daos.NewSQLiteDAO(db), false, false, "")}
+	response := server.do(http.MethodPost, "/api/auth/register", `{"Email":"test@example.com","Password":"password123"}`)
+	if response.Code != http.StatusCreated {
+		t.Fatalf("register: %d %s", response.Code, response.Body)
+	}
+	server.cookies = response.Result().Cookies()
+	return server
+}
+
+// do sends a request with the session cookie and returns the response
+func (s *testServer) do(method, path, body string) *httptest.ResponseRecorder {
+	s.t.Helper()
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	for _, cookie := range s.cookies {
+		request.AddCookie(cookie)
+	}
+	response := httptest.NewRecorder()
+	s.router.ServeHTTP(response, request)
+	return response
+}
+
+func TestTitlesWithSlashes(t *testing.T) {
+	server := newTestServer(t)
+
+	steps := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/api/movies", `{"Title":"Face/Off","Tier":"A"}`, http.StatusCreated},
+		{http.MethodPut, "/api/movies/Face%2FOff", `{"Title":"Face/Off","Tier":"A","Notes":"Twice"}`, http.StatusOK},
+		{http.MethodPut, "/api/movies/Face%2FOff/tier", `{"Tier":"S","Position":0}`, http.StatusOK},
+		{http.MethodDelete, "/api/movies/Face%2FOff", "", http.StatusOK},
+		{http.MethodDelete, "/api/movies/Face%2FOff", "", http.StatusNotFound},
+
+		{http.MethodPost, "/api/books", `{"Title":"1/2 a book"}`, http.StatusCreated},
+		{http.MethodGet, "/api/books/1%2F2%20a%20book", "", http.StatusOK},
+		{http.MethodPut, "/api/books/1%2F2%20a%20book", `{"Title":"1/2 a book","Pages":12}`, http.StatusOK},
+		{http.MethodPost, "/api/books/1%2F2%20a%20book/sessions", `{"Date":"2024-01-02","PagesRead":5}`, http.StatusCreated},
+		{http.MethodGet, "/api/books/1%2F2%20a%20book/sessions", "", http.StatusOK},
+		{http.MethodDelete, "/api/books/1%2F2%20a%20book", "", http.StatusOK},
+		{http.MethodGet, "/api/books/1%2F2%20a%20book", "", http.StatusNotFound},
+
+		{http.MethodPost, "/api/tv", `{"Title":"Us/Them"}`, http.StatusCreated},
+		{http.MethodGet, "/api/tv/Us%2FThem", "", http.StatusOK},
+		{http.MethodPut, "/api/tv/Us%2FThem/seasons/1", `{"Number":1,"Episodes":[{"Number":1,"Title":"Pilot"}]}`, http.StatusOK},
+		{http.MethodPut, "/api/tv/Us%2FThem/seasons/1/episodes/1/watched", `{"Date":"2024-01-02"}`, http.StatusOK},
+		{http.MethodPut, "/api/tv/Us%2FThem", `{"Title":"Us/Them","Notes":"Good"}`, http.StatusOK},
+		{http.MethodDelete, "/api/tv/Us%2FThem", "", http.StatusOK},
+		{http.MethodGet, "/api/tv/Us%2FThem", "", http.StatusNotFound},
+	}
+	for _, step := range steps {
+		response := server.do(step.method, step.path, step.body)
+		if response.Code != step.want {
+			t.Errorf("%s %s = %d %s, want %d", step.method, step.path, response.Code, response.Body, step.want)
+		}
+	}
+}
+
+func TestFoodVisitRoutes(t *testing.T) {
+	server := newTestServer(t)
+
+	steps := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/api/food", `{"Name":"Pancakes","Location":"Amsterdam"}`, http.StatusCreated},
+		{http.MethodGet, "/api/food/Amsterdam", "", http.StatusOK},
+		{http.MethodPost, "/api/food/Pancakes/visits", `{"Date":"2024-01-02","Cost":12}`, http.StatusCreated},
+		{http.MethodGet, "/api/food/Pancakes/visits", "", http.StatusOK},
+		{http.MethodGet, "/api/food/Pancakes/visits/1", "", http.StatusOK},
+		{http.MethodPut, "/api/food/Pancakes/visits/1", `{"Date":"2024-01-03","Cost":14}`, http.StatusOK},
+		{http.MethodDelete, "/api/food/Pancakes/visits/1", "", http.StatusOK},
+		{http.MethodGet, "/api/food/Pancakes/visits/1", "", http.StatusNotFound},
+
+		// A place named like one of the fixed /api/food routes still has its visits
+		{http.MethodPost, "/api/food", `{"Name":"pick","Location":"Amsterdam"}`, http.StatusCreated},
+		{http.MethodPost, "/api/food/pick/visits", `{"Date":"2024-01-02"}`, http.StatusCreated},
+		{http.MethodGet, "/api/food/pick/visits", "", http.StatusOK},
+	}
+	for _, step := range steps {
+		response := server.do(step.method, step.path, step.body)
+		if response.Code != step.want {
+			t.Errorf("%s %s = %d %s, want %d", step.method, step.path, response.Code, response.Body, step.want)
+		}
+	}
+}
+
+func TestPeopleRejectImpossibleBirthdays(t *testing.T) {
+	server := newTestServer(t)
+
+	steps := []struct {
+		method, path, body string
+		want               int
+	}{
+		{http.MethodPost, "/api/people", `{"First":"Ada","BirthMonth":2,"BirthDay":30}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/people", `{"First":"Ada","BirthYear":1990,"BirthMonth":2,"BirthDay":29}`, http.StatusBadRequest},
+		{http.MethodPost, "/api/people", `{"First":"Ada","BirthMonth":2,"BirthDay":29}`, http.StatusCreated},
+		{http.MethodPut, "/api/people/1", `{"First":"Ada","BirthYear":1990,"BirthMonth":4,"BirthDay":31}`, http.StatusBadRequest},
+		{http.MethodPut, "/api/people/1", `{"First":"Ada","BirthYear":1990,"BirthMonth":4,"BirthDay":30}`, http.StatusOK},
+	}
+	for _, step := range steps {
+		response := server.do(step.method, step.path, step.body)
+		if response.Code != step.want {
+			t.Errorf("%s %s = %d %s, want %d", step.method, step.path, response.Code, response.Body, step.want)
+		}
+	}
+}
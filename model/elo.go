@@ -0,0 +1,81 @@
+package model
+
+import (
+	"math"
+	"slices"
+)
+
+// ComparisonCategories lists the item categories that can be ranked by pairwise comparison
+var ComparisonCategories = []string{"movies", "books"}
+
+const (
+	// EloInitialScore is the score of an item that has never been compared
+	EloInitialScore = 1500.0
+	// eloK controls how far a single comparison moves the scores
+	eloK = 32.0
+)
+
+// ValidComparisonCategory reports whether category is one of ComparisonCategories
+func ValidComparisonCategory(category string) bool {
+	return slices.Contains(ComparisonCategories, category)
+}
+
+// EloUpdate returns the new scores after the item scored winner beat the item scored loser
+func EloUpdate(winner, loser float64) (float64, float64) {
+	expected := 1 / (1 + math.Pow(10, (loser-winner)/400))
+	change := eloK * (1 - expected)
+	return winner + change, loser - change
+}
+
+// SuggestTiers groups a ranking (best first) into S-D tiers by how far each score sits
+// from the mean, measured in standard deviations
+func SuggestTiers(ranking []RankedItem) []TierSuggestion {
+	suggestions := []TierSuggestion{
+		{Tier: "S", Titles: []string{}},
+		{Tier: "A", Titles: []string{}},
+		{Tier: "B", Titles: []string{}},
+		{Tier: "C", Titles: []string{}},
+		{Tier: "D", Titles: []string{}},
+	}
+	if len(ranking) == 0 {
+		return suggestions
+	}
+
+	var mean, variance float64
+	for _, item := range ranking {
+		mean += item.Score
+	}
+	mean /= float64(len(ranking))
+	for _, item := range ranking {
+		variance += (item.Score - mean) * (item.Score - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(ranking)))
+
+	// Nothing separates the items yet, so they all sit in the middle
+	if stddev == 0 {
+		for i := range suggestions {
+			suggestions[i].MinScore = mean
+		}
+		for _, item := range ranking {
+			suggestions[2].Titles = append(suggestions[2].Titles, item.Title)
+		}
+		return suggestions
+	}
+
+	// Lower score bound of S, A, B and C; D takes everything below C
+	cutoffs := []float64{1.5, 0.5, -0.5, -1.5}
+	for i, cutoff := range cutoffs {
+		suggestions[i].MinScore = mean + cutoff*stddev
+	}
+	suggestions[len(suggestions)-1].MinScore = min(ranking[len(ranking)-1].Score, suggestions[len(cutoffs)-1].MinScore)
+
+	for _, item := range ranking {
+		i := 0
+		for i < len(cutoffs) && item.Score < suggestions[i].MinScore {
+			i++
+		}
+		suggestions[i].Titles = append(suggestions[i].Titles, item.Title)
+	}
+
+	return suggestions
+}
@@ -1,5 +1,19 @@
 package model
 
+import (
+	"errors"
+	"slices"
+	"time"
+)
+
+// Errors returned by DAO methods that handlers map to HTTP status codes
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("already exists")
+	ErrInvalidReference   = errors.New("referenced row does not exist")
+	ErrRegistrationClosed = errors.New("registration is closed")
+)
+
 // DAO interface
 type LifeJournalDAO interface {
 	// Concert methods
@@ -8,19 +22,127 @@ type LifeJournalDAO interface {
 	// Movie methods
 	GetAllMovies() ([]Movie, error)
 	GetMoviesByTier(tier string) ([]Movie, error)
+	CreateMovie(movie Movie) error
+	UpdateMovie(title string, movie Movie) error
+	DeleteMovie(title string) error
+	MoveMovie(title, tier string, position int) error
+	ReorderTier(tier string, titles []string) error
+
+	// Comparison methods
+	GetComparisonPair(category string) (*ComparisonPair, error)
+	RecordComparison(category, winner, loser string) error
+	GetComparisonRanking(category string) ([]RankedItem, error)
+
+	// Theater movie methods
+	GetTheaterMovies(fromYear, toYear int) ([]TheaterMovie, error)
+	GetTheaterMovie(id int) (*TheaterMovie, error)
+	CreateTheaterMovie(movie TheaterMovie) (int, error)
+	UpdateTheaterMovie(id int, movie TheaterMovie) error
+	DeleteTheaterMovie(id int) error
+
+	// Travel methods
+	GetTrips(fromYear, toYear int) ([]Trip, error)
+	GetTrip(id int) (*Trip, error)
+	CreateTrip(trip Trip) (int, error)
+	UpdateTrip(id int, trip Trip) error
+	DeleteTrip(id int) error
+
+	// Life event methods
+	GetLifeEvents() ([]LifeEvent, error)
+	GetLifeEvent(id int) (*LifeEvent, error)
+	CreateLifeEvent(event LifeEvent) (int, error)
+	UpdateLifeEvent(id int, event LifeEvent) error
+	DeleteLifeEvent(id int) error
+
+	// Memory methods
+	GetMemories(from, to string) ([]Memory, error)
+	GetMemory(id int) (*Memory, error)
+	GetRandomMemory() (*Memory, error)
+	CreateMemory(memory Memory) (int, error)
+	UpdateMemory(id int, memory Memory) error
+	DeleteMemory(id int) error
+
+	// Video game methods
+	GetVideoGames(status, platform string) ([]VideoGame, error)
+	GetVideoGame(id int) (*VideoGame, error)
+	CreateVideoGame(game VideoGame) (int, error)
+	UpdateVideoGame(id int, game VideoGame) error
+	DeleteVideoGame(id int) error
 
 	// Book methods
-	GetAllBooks() ([]Book, error)
+	GetBooks(filter BookFilter) ([]Book, error)
+	GetBook(title string) (*Book, error)
+	CreateBook(book Book) error
+	UpdateBook(title string, book Book) error
+	DeleteBook(title string) error
+	GetSeries(name string) ([]Book, error)
+
+	// Reading methods
+	GetReadingSessions(title string) ([]ReadingSession, error)
+	CreateReadingSession(session ReadingSession) (int, error)
+	DeleteReadingSession(id int) error
+	GetReadingGoal(year int) (*ReadingGoal, error)
+	SetReadingGoal(goal ReadingGoal) error
+	GetReadingStats(year int) (*ReadingStats, error)
 
 	// Food methods
 	GetAllFoodPlaces() ([]FoodPlace, error)
 	GetFoodPlacesByLocation(location string) ([]FoodPlace, error)
+	GetFoodLocations() ([]Location, error)
+	SetFoodPlaceCoordinates(name string, latitude, longitude *float64) error
+	CreateFoodPlace(place FoodPlace) (string, error)
+	DeleteFoodPlace(name string) error
+	GetFoodVisits(place string) ([]FoodVisit, error)
+	GetFoodVisit(place string, id int) (*FoodVisit, error)
+	CreateFoodVisit(visit FoodVisit) (int, error)
+	UpdateFoodVisit(place string, id int, visit FoodVisit) error
+	DeleteFoodVisit(place string, id int) error
+
+	// User methods. ForUser scopes every other method to the rows one user owns.
+	ForUser(uuid string) LifeJournalDAO
+	CreateUser(user User) error
+	CreateFirstUser(user User) error
+	ClaimUnownedRows(uuid string) (int, error)
+	GetUserByEmail(email string) (*User, error)
+	UpdateUserPassword(uuid, passwordHash, salt string) error
+	CreateSession(tokenHash, userUUID string, expires time.Time) error
+	GetSessionUser(tokenHash string) (*User, error)
+	DeleteSession(tokenHash string) error
+	DeleteUserSessions(userUUID, keepTokenHash string) error
+
+	// Map methods
+	GetMapPoints() ([]MapPoint, error)
+
+	// Timeline methods
+	GetTimeline(query TimelineQuery) ([]TimelineItem, error)
+	GetOnThisDay(month, day int) ([]TimelineItem, error)
 
 	// People methods
 	GetAllPeople() ([]Person, error)
+	GetPerson(id int) (*Person, error)
+	CreatePerson(person Person) (int, error)
+	CreatePeople(people []Person) ([]int, error)
+	UpdatePerson(id int, person Person) error
+	DeletePerson(id int) error
+	GetPersonTimeline(personID int) ([]TimelineItem, error)
+	GetInteractions(personID int) ([]Interaction, error)
+	CreateInteraction(interaction Interaction) (int, error)
+	DeleteInteraction(id int) error
+	GetRelationships() ([]Relationship, error)
+	CreateRelationship(relationship Relationship) (int, error)
+	DeleteRelationship(id int) error
+	LinkPeopleFromText() (*PeopleLinkReport, error)
 
 	// TV methods
 	GetAllTVShows() ([]TVShow, error)
+	GetTVShow(title string) (*TVShow, error)
+	CreateTVShow(show TVShow) error
+	UpdateTVShow(title string, show TVShow) error
+	DeleteTVShow(title string) error
+	SaveTVSeason(title string, season TVSeason) error
+	DeleteTVSeason(title string, season int) error
+	MarkTVSeasonWatched(title string, season int, date string) error
+	SetTVEpisodeWatched(title string, season, episode int, date string) error
 
 	// Journal methods
 	GetAllJournalEntries() ([]JournalEntry, error)
@@ -39,29 +161,240 @@ type Concert struct {
 	People  string `json:"People"`
 }
 
+// MovieTiers lists the valid movie tiers from best to worst
+var MovieTiers = []string{"S", "A", "B", "C", "D"}
+
+// ValidMovieTier reports whether tier is one of MovieTiers
+func ValidMovieTier(tier string) bool {
+	return slices.Contains(MovieTiers, tier)
+}
+
 // Movie represents a movie entry
 type Movie struct {
+	Title       string `json:"Title"`
+	Tier        string `json:"Tier"`
+	Position    int    `json:"Position"`
+	Rating      string `json:"Rating"`
+	Notes       string `json:"Notes"`
+	WatchedDate string `json:"WatchedDate"`
+}
+
+// TheaterMovie represents a trip to the movie theater
+type TheaterMovie struct {
+	ID        int    `json:"ID"`
+	Title     string `json:"Title"`
+	Date      string `json:"Date"`
+	People    string `json:"People"`
+	PeopleIDs []int  `json:"PeopleIDs"`
+	Notes     string `json:"Notes"`
+	// WatchedMovie is the watched_movies row with the same title, if there is one
+	WatchedMovie *Movie `json:"WatchedMovie"`
+}
+
+// Trip represents a trip from the travel log
+type Trip struct {
+	ID        int         `json:"ID"`
+	Title     string      `json:"Title"`
+	StartDate string      `json:"StartDate"`
+	EndDate   string      `json:"EndDate"`
+	Places    []TripPlace `json:"Places"`
+	People    string      `json:"People"`
+	PeopleIDs []int       `json:"PeopleIDs"`
+	Notes     string      `json:"Notes"`
+}
+
+// TripPlace is a stop on a trip, in the order it was visited
+type TripPlace struct {
+	Name      string   `json:"Name"`
+	Latitude  *float64 `json:"Latitude"`
+	Longitude *float64 `json:"Longitude"`
+}
+
+// LifeEvent represents a milestone. Its date may be partial: Month and Day are 0 when unknown.
+type LifeEvent struct {
+	ID    int    `json:"ID"`
 	Title string `json:"Title"`
-	Tier  string `json:"Tier"`
+	Year  int    `json:"Year"`
+	Month int    `json:"Month"`
+	Day   int    `json:"Day"`
+	Notes string `json:"Notes"`
+}
+
+// Memory represents a small moment worth remembering
+type Memory struct {
+	ID        int    `json:"ID"`
+	Date      string `json:"Date"`
+	Notes     string `json:"Notes"`
+	People    string `json:"People"`
+	PeopleIDs []int  `json:"PeopleIDs"`
+}
+
+// ComparisonPair is two items of a category to choose between
+type ComparisonPair struct {
+	Category string `json:"Category"`
+	A        string `json:"A"`
+	B        string `json:"B"`
+}
+
+// RankedItem is an item's place in a category ranked by comparison score
+type RankedItem struct {
+	Rank        int     `json:"Rank"`
+	Title       string  `json:"Title"`
+	Score       float64 `json:"Score"`
+	Comparisons int     `json:"Comparisons"`
+}
+
+// TierSuggestion is a tier derived from comparison scores and the items that fall in it
+type TierSuggestion struct {
+	Tier     string   `json:"Tier"`
+	MinScore float64  `json:"MinScore"`
+	Titles   []string `json:"Titles"`
+}
+
+// GameStatuses lists the valid video game statuses in the order a game moves through them
+var GameStatuses = []string{"backlog", "playing", "completed", "dropped"}
+
+// ValidGameStatus reports whether status is one of GameStatuses
+func ValidGameStatus(status string) bool {
+	return slices.Contains(GameStatuses, status)
+}
+
+// VideoGame represents a video game entry
+type VideoGame struct {
+	ID          int     `json:"ID"`
+	Title       string  `json:"Title"`
+	Platform    string  `json:"Platform"`
+	Status      string  `json:"Status"`
+	HoursPlayed float64 `json:"HoursPlayed"`
+	Rating      float64 `json:"Rating"`
+	Multiplayer bool    `json:"Multiplayer"`
+	Notes       string  `json:"Notes"`
 }
 
 // Book represents a book entry
 type Book struct {
-	Title    string  `json:"Title"`
-	Rating   float64 `json:"Rating"`
-	Pages    int     `json:"Pages"`
-	Author   string  `json:"Author"`
-	Series   string  `json:"Series"`
-	Finished bool    `json:"Finished"`
+	Title          string  `json:"Title"`
+	Rating         float64 `json:"Rating"`
+	Pages          int     `json:"Pages"`
+	Author         string  `json:"Author"`
+	Series         string  `json:"Series"`
+	SeriesSequence int     `json:"SeriesSequence"`
+	Finished       bool    `json:"Finished"`
+	DateFinished   string  `json:"DateFinished"`
+	Owned          bool    `json:"Owned"`
+	CurrentPage    int     `json:"CurrentPage"`
+}
+
+// BookFilter narrows a book listing. Nil flags and an empty author match every book.
+type BookFilter struct {
+	Finished *bool
+	Owned    *bool
+	Author   string
+}
+
+// BookSeries is a series in reading order along with how much of it has been read
+type BookSeries struct {
+	Name   string `json:"Name"`
+	Books  []Book `json:"Books"`
+	Read   int    `json:"Read"`
+	Unread int    `json:"Unread"`
+}
+
+// ReadingSession is one sitting spent reading a book
+type ReadingSession struct {
+	ID        int    `json:"ID"`
+	BookTitle string `json:"BookTitle"`
+	Date      string `json:"Date"`
+	PagesRead int    `json:"PagesRead"`
+	Minutes   int    `json:"Minutes"`
+}
+
+// ReadingGoalUnits are what a yearly reading goal can count
+var ReadingGoalUnits = []string{"books", "pages"}
+
+// ReadingGoal is the number of books or pages to read in a year
+type ReadingGoal struct {
+	Year   int    `json:"Year"`
+	Target int    `json:"Target"`
+	Unit   string `json:"Unit"`
+}
+
+// ReadingStats summarizes a year of reading. Books count when their finish date falls in
+// the year; pages and minutes come from reading sessions.
+type ReadingStats struct {
+	Year          int          `json:"Year"`
+	Goal          *ReadingGoal `json:"Goal"`
+	GoalProgress  float64      `json:"GoalProgress"` // percent of the goal reached, 0 without a goal
+	BooksFinished int          `json:"BooksFinished"`
+	PagesRead     int          `json:"PagesRead"`
+	MinutesRead   int          `json:"MinutesRead"`
+	Sessions      int          `json:"Sessions"`
+	PagesPerMonth []int        `json:"PagesPerMonth"` // January first
+	PagesPerHour  float64      `json:"PagesPerHour"`  // over sessions with a recorded duration
+	PagesPerDay   float64      `json:"PagesPerDay"`   // over the days of the year so far
 }
 
 // FoodPlace represents a food place entry
 type FoodPlace struct {
-	Name     string `json:"Name"`
-	Location string `json:"Location"`
-	Notes    string `json:"Notes"`
-	Type     string `json:"Type"`
-	Category string `json:"Category"`
+	Name      string   `json:"Name"`
+	Location  string   `json:"Location"`
+	Notes     string   `json:"Notes"`
+	Type      string   `json:"Type"`
+	Category  string   `json:"Category"`
+	Latitude  *float64 `json:"Latitude"`
+	Longitude *float64 `json:"Longitude"`
+	// Wishlist marks a place we want to try but haven't been to yet
+	Wishlist bool `json:"Wishlist"`
+	// Visits, AverageRating and LastVisit summarize the visit log. AverageRating is over
+	// every rated dish and nil when none have been rated.
+	Visits        int      `json:"Visits"`
+	AverageRating *float64 `json:"AverageRating"`
+	LastVisit     string   `json:"LastVisit"`
+}
+
+// Location is a normalized city that food places are filed under. Count is the number of
+// food places there.
+type Location struct {
+	ID      int    `json:"ID"`
+	City    string `json:"City"`
+	Region  string `json:"Region"`
+	Country string `json:"Country"`
+	Name    string `json:"Name"`
+	Count   int    `json:"Count"`
+}
+
+// FoodPick is a randomly chosen place to eat along with the odds it had of being chosen
+type FoodPick struct {
+	Place      FoodPlace `json:"Place"`
+	Chance     float64   `json:"Chance"` // 0-1
+	Candidates int       `json:"Candidates"`
+}
+
+// FoodVisit is one meal at a food place
+type FoodVisit struct {
+	ID        int     `json:"ID"`
+	Place     string  `json:"Place"`
+	Date      string  `json:"Date"`
+	Cost      float64 `json:"Cost"`
+	Notes     string  `json:"Notes"`
+	Dishes    []Dish  `json:"Dishes"`
+	PeopleIDs []int   `json:"PeopleIDs"`
+}
+
+// Dish is something ordered on a visit. Rating is out of 10 and nil when not rated.
+type Dish struct {
+	Name   string   `json:"Name"`
+	Rating *float64 `json:"Rating"`
+}
+
+// MapPoint is a geolocated item from any category
+type MapPoint struct {
+	Type        string  `json:"Type"`
+	Name        string  `json:"Name"`
+	Description string  `json:"Description"`
+	Link        string  `json:"Link"`
+	Latitude    float64 `json:"Latitude"`
+	Longitude   float64 `json:"Longitude"`
 }
 
 // Person represents a person entry
@@ -74,17 +407,218 @@ type Person struct {
 	BirthDay   int    `json:"BirthDay"`
 	BirthMonth int    `json:"BirthMonth"`
 	BirthYear  int    `json:"BirthYear"`
-	GiftIdeas  string `json:"GiftIdeas"`
 	Email      string `json:"Email"`
 	Category   string `json:"Category"`
 	Notes      string `json:"Notes"`
+	// GiftIdeas are kept in the order they were listed
+	GiftIdeas []GiftIdea `json:"GiftIdeas"`
+	// ContactEveryDays is how often we want to be in touch, 0 for no reminders. LastContacted is
+	// the date of the latest interaction and can't be set directly.
+	ContactEveryDays int    `json:"ContactEveryDays"`
+	LastContacted    string `json:"LastContacted"`
+}
+
+// InteractionKinds are the ways of being in touch with someone
+var InteractionKinds = []string{"call", "visit", "message"}
+
+// Interaction is a time we were in touch with a person
+type Interaction struct {
+	ID       int    `json:"ID"`
+	PersonID int    `json:"PersonID"`
+	Date     string `json:"Date"`
+	Kind     string `json:"Kind"`
+	Notes    string `json:"Notes"`
+}
+
+// OverduePerson is someone we haven't been in touch with as often as we'd like.
+// DaysSinceContact is nil when we've never recorded an interaction with them.
+type OverduePerson struct {
+	Person           Person `json:"Person"`
+	DaysSinceContact *int   `json:"DaysSinceContact"`
+	DaysOverdue      int    `json:"DaysOverdue"`
+}
+
+// UpcomingBirthday is the next birthday of a person. Turning is the age they turn and 0 when
+// their birth year is unknown.
+type UpcomingBirthday struct {
+	PersonID  int    `json:"PersonID"`
+	Name      string `json:"Name"`
+	Date      string `json:"Date"`
+	DaysUntil int    `json:"DaysUntil"`
+	Turning   int    `json:"Turning"`
+}
+
+// GiftStatuses are the stages of a gift idea, in order
+var GiftStatuses = []string{"idea", "bought", "given"}
+
+// GiftIdea is something to give a person. Occasion is free text like "Birthday 2026" and
+// Price is nil when unknown.
+type GiftIdea struct {
+	Idea     string   `json:"Idea"`
+	Status   string   `json:"Status"`
+	Occasion string   `json:"Occasion"`
+	Price    *float64 `json:"Price"`
+}
+
+// TimelineItem is the common envelope for dated items from any category
+type TimelineItem struct {
+	Type    string `json:"Type"`
+	Date    string `json:"Date"`
+	Title   string `json:"Title"`
+	Summary string `json:"Summary"`
+	Link    string `json:"Link"`
+}
+
+// TimelineTypes lists the categories merged into the timeline
+var TimelineTypes = []string{"journal", "concert", "theater_movie", "travel", "life_event", "book", "memory"}
+
+// TimelineQuery selects a page of the timeline. From and To are inclusive YYYY-MM-DD dates
+// and empty when that end is open; an empty Types selects every type.
+type TimelineQuery struct {
+	From   string
+	To     string
+	Types  []string
+	Limit  int
+	Offset int
+}
+
+// TimelinePage is one page of the timeline, newest first
+type TimelinePage struct {
+	Items   []TimelineItem `json:"Items"`
+	Limit   int            `json:"Limit"`
+	Offset  int            `json:"Offset"`
+	HasMore bool           `json:"HasMore"`
+}
+
+// OnThisDay is everything that happened on a calendar day, grouped by year with the most recent first
+type OnThisDay struct {
+	Date  string          `json:"Date"`
+	Years []OnThisDayYear `json:"Years"`
+}
+
+// OnThisDayYear is everything that happened on the day in one year
+type OnThisDayYear struct {
+	Year  int            `json:"Year"`
+	Items []TimelineItem `json:"Items"`
+}
+
+// RelationshipType is a kind of relationship. Directed types read one way, "Ann is Bob's
+// parent", and Inverse is how they read from the other end; undirected types are their own inverse.
+type RelationshipType struct {
+	Name     string `json:"Name"`
+	Inverse  string `json:"Inverse"`
+	Directed bool   `json:"Directed"`
+	Family   bool   `json:"Family"`
+}
+
+// RelationshipTypes are the relationships that can be recorded between people
+var RelationshipTypes = []RelationshipType{
+	{Name: "spouse", Inverse: "spouse", Family: true},
+	{Name: "partner", Inverse: "partner", Family: true},
+	{Name: "parent", Inverse: "child", Directed: true, Family: true},
+	{Name: "child", Inverse: "parent", Directed: true, Family: true},
+	{Name: "sibling", Inverse: "sibling", Family: true},
+	{Name: "coworker", Inverse: "coworker"},
+	{Name: "friend", Inverse: "friend"},
+	{Name: "friend-of", Inverse: "friend", Directed: true},
+}
+
+// Relationship is an edge between two people, read as "PersonID is RelatedID's Type".
+// RelatedID is 0 for a relationship to us.
+type Relationship struct {
+	ID        int    `json:"ID"`
+	PersonID  int    `json:"PersonID"`
+	RelatedID int    `json:"RelatedID"`
+	Type      string `json:"Type"`
+	Notes     string `json:"Notes"`
+}
+
+// PersonRelation is a relationship seen from one person: Name is their Relation. PersonID is
+// the other person, 0 for us.
+type PersonRelation struct {
+	RelationshipID int    `json:"RelationshipID"`
+	PersonID       int    `json:"PersonID"`
+	Name           string `json:"Name"`
+	Relation       string `json:"Relation"`
+	Notes          string `json:"Notes"`
+}
+
+// PeopleGraph is the relationship graph in a node and edge form ready to render. Node 0 is us.
+type PeopleGraph struct {
+	Nodes []GraphNode `json:"Nodes"`
+	Edges []GraphEdge `json:"Edges"`
+}
+
+// GraphNode is a person in the relationship graph. Generation places them in a family tree,
+// parents one above their children. It counts from us at 0, or from the eldest generation in
+// a family we aren't connected to, and is nil for people with no family relationships.
+type GraphNode struct {
+	ID         int    `json:"ID"`
+	Name       string `json:"Name"`
+	Category   string `json:"Category"`
+	Generation *int   `json:"Generation"`
+}
+
+// GraphEdge is a relationship in the graph, pointing from Source to Target when Directed
+type GraphEdge struct {
+	ID       int    `json:"ID"`
+	Source   int    `json:"Source"`
+	Target   int    `json:"Target"`
+	Type     string `json:"Type"`
+	Directed bool   `json:"Directed"`
+}
+
+// PeopleImportReport lists the people added from a vCard file and the contacts left out
+type PeopleImportReport struct {
+	Imported []Person         `json:"Imported"`
+	Skipped  []SkippedContact `json:"Skipped"`
+}
+
+// SkippedContact is a vCard contact that wasn't imported. PersonID is the existing person it
+// duplicates, or 0 when it was skipped for another reason.
+type SkippedContact struct {
+	Name     string `json:"Name"`
+	Reason   string `json:"Reason"`
+	PersonID int    `json:"PersonID"`
+}
+
+// PeopleLinkReport summarizes linking free text people columns to the people table
+type PeopleLinkReport struct {
+	Linked    int               `json:"Linked"`
+	Unmatched []UnmatchedPerson `json:"Unmatched"`
+}
+
+// UnmatchedPerson is a name from a free text people column with no matching person
+type UnmatchedPerson struct {
+	Type string `json:"Type"`
+	Ref  string `json:"Ref"`
+	Name string `json:"Name"`
 }
 
 // TVShow represents a TV show entry
 type TVShow struct {
-	Title          string `json:"Title"`
-	Notes          string `json:"Notes"`
-	SeasonsWatched string `json:"SeasonsWatched"`
+	Title string `json:"Title"`
+	Notes string `json:"Notes"`
+	// SeasonsWatched is free text from before seasons were tracked episode by episode
+	SeasonsWatched string     `json:"SeasonsWatched"`
+	ChildhoodShow  bool       `json:"ChildhoodShow"`
+	Seasons        []TVSeason `json:"Seasons"`
+	// NextEpisode is the episode after the furthest one watched, nil once the show is caught up
+	NextEpisode *TVEpisode `json:"NextEpisode"`
+}
+
+// TVSeason is one season of a show with its episodes in order
+type TVSeason struct {
+	Number   int         `json:"Number"`
+	Episodes []TVEpisode `json:"Episodes"`
+}
+
+// TVEpisode is a single episode. WatchedDate is empty until it has been watched.
+type TVEpisode struct {
+	Season      int    `json:"Season"`
+	Number      int    `json:"Number"`
+	Title       string `json:"Title"`
+	WatchedDate string `json:"WatchedDate"`
 }
 
 // JournalEntry represents a journal entry
@@ -99,8 +633,32 @@ type JournalEntry struct {
 
 // Photo represents a photo/file entry
 type Photo struct {
-	ID       int    `json:"id"`
-	FileName string `json:"fileName"`
-	Bytes    []byte `json:"-"`
-	Created  string `json:"created"`
+	ID        int      `json:"id"`
+	FileName  string   `json:"fileName"`
+	Bytes     []byte   `json:"-"`
+	Created   string   `json:"created"`
+	Taken     string   `json:"taken"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+}
+
+// User is an account that can sign in. The password hash and salt never leave the server.
+type User struct {
+	UUID         string `json:"UUID"`
+	Email        string `json:"Email"`
+	Created      string `json:"Created"`
+	PasswordHash string `json:"-"`
+	Salt         string `json:"-"`
+}
+
+// Credentials are what a user registers or signs in with
+type Credentials struct {
+	Email    string `json:"Email"`
+	Password string `json:"Password"`
+}
+
+// PasswordChange replaces a signed in user's password
+type PasswordChange struct {
+	CurrentPassword string `json:"CurrentPassword"`
+	NewPassword     string `json:"NewPassword"`
 }
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strconv"
+
+	. "memories/model"
+)
+
+// groupByYear splits items sorted newest first into one group per year, keeping that order
+func groupByYear(items []TimelineItem) []OnThisDayYear {
+	years := []OnThisDayYear{}
+	for _, item := range items {
+		if len(item.Date) < 4 {
+			continue
+		}
+		year, err := strconv.Atoi(item.Date[:4])
+		if err != nil {
+			continue
+		}
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, OnThisDayYear{Year: year})
+		}
+		last := &years[len(years)-1]
+		last.Items = append(last.Items, item)
+	}
+	return years
+}
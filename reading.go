@@ -0,0 +1,31 @@
+package main
+
+import (
+	"time"
+
+	. "memories/model"
+)
+
+// completeReadingStats fills in the parts of a year's reading stats that depend on today's
+// date: goal progress and the average pages read per day so far
+func completeReadingStats(stats *ReadingStats, now time.Time) {
+	if stats.Goal != nil && stats.Goal.Target > 0 {
+		done := stats.BooksFinished
+		if stats.Goal.Unit == "pages" {
+			done = stats.PagesRead
+		}
+		stats.GoalProgress = float64(done) / float64(stats.Goal.Target) * 100
+	}
+
+	// A finished year counts all of its days, the current one the days so far, a future one none
+	days := 0
+	switch {
+	case now.Year() > stats.Year:
+		days = time.Date(stats.Year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+	case now.Year() == stats.Year:
+		days = now.YearDay()
+	}
+	if days > 0 {
+		stats.PagesPerDay = float64(stats.PagesRead) / float64(days)
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"slices"
+
+	. "memories/model"
+)
+
+// usName is how we appear in relationships and the graph
+const usName = "Me"
+
+// relationshipType looks up a relationship type by name
+func relationshipType(name string) (RelationshipType, bool) {
+	i := slices.IndexFunc(RelationshipTypes, func(t RelationshipType) bool { return t.Name == name })
+	if i < 0 {
+		return RelationshipType{}, false
+	}
+	return RelationshipTypes[i], true
+}
+
+// personRelations lists a person's relationships from their side, naming the other person
+func personRelations(personID int, people []Person, relationships []Relationship) []PersonRelation {
+	names := map[int]string{0: usName}
+	for _, person := range people {
+		names[person.ID] = personName(person)
+	}
+
+	relations := []PersonRelation{}
+	for _, relationship := range relationships {
+		relation := PersonRelation{RelationshipID: relationship.ID, Notes: relationship.Notes}
+		switch personID {
+		case relationship.PersonID:
+			relation.PersonID = relationship.RelatedID
+			relation.Relation = relationship.Type
+		case relationship.RelatedID:
+			t, _ := relationshipType(relationship.Type)
+			relation.PersonID = relationship.PersonID
+			relation.Relation = t.Inverse
+		default:
+			continue
+		}
+		relation.Name = names[relation.PersonID]
+		relations = append(relations, relation)
+	}
+	return relations
+}
+
+// buildPeopleGraph turns people and their relationships into nodes and edges, keeping only the
+// given relationship types when there are any. Everyone is a node, along with us.
+func buildPeopleGraph(people []Person, relationships []Relationship, types []string) PeopleGraph {
+	graph := PeopleGraph{Nodes: []GraphNode{{ID: 0, Name: usName}}, Edges: []GraphEdge{}}
+	for _, person := range people {
+		graph.Nodes = append(graph.Nodes, GraphNode{ID: person.ID, Name: personName(person), Category: person.Category})
+	}
+	for _, relationship := range relationships {
+		if len(types) > 0 && !slices.Contains(types, relationship.Type) {
+			continue
+		}
+		t, _ := relationshipType(relationship.Type)
+		graph.Edges = append(graph.Edges, GraphEdge{
+			ID:       relationship.ID,
+			Source:   relationship.PersonID,
+			Target:   relationship.RelatedID,
+			Type:     relationship.Type,
+			Directed: t.Directed,
+		})
+	}
+
+	assignGenerations(graph)
+	return graph
+}
+
+// assignGenerations walks the family edges of the graph to place each node in a generation,
+// starting from us and then from each family we aren't connected to
+func assignGenerations(graph PeopleGraph) {
+	// offsets[a][b] is b's generation minus a's along a family edge
+	offsets := make(map[int]map[int]int)
+	link := func(a, b, offset int) {
+		if offsets[a] == nil {
+			offsets[a] = make(map[int]int)
+		}
+		offsets[a][b] = offset
+	}
+	for _, edge := range graph.Edges {
+		t, _ := relationshipType(edge.Type)
+		if !t.Family {
+			continue
+		}
+		offset := 0
+		switch edge.Type {
+		case "parent":
+			offset = 1
+		case "child":
+			offset = -1
+		}
+		link(edge.Source, edge.Target, offset)
+		link(edge.Target, edge.Source, -offset)
+	}
+
+	generations := make(map[int]int)
+	for _, node := range graph.Nodes {
+		if _, done := generations[node.ID]; done || offsets[node.ID] == nil {
+			continue
+		}
+
+		// Breadth first from this node, then shift another family so its eldest generation is 0
+		component := []int{node.ID}
+		generations[node.ID] = 0
+		for i := 0; i < len(component); i++ {
+			current := component[i]
+			for next, offset := range offsets[current] {
+				if _, seen := generations[next]; !seen {
+					generations[next] = generations[current] + offset
+					component = append(component, next)
+				}
+			}
+		}
+		if node.ID != 0 {
+			eldest := generations[node.ID]
+			for _, id := range component {
+				eldest = min(eldest, generations[id])
+			}
+			for _, id := range component {
+				generations[id] -= eldest
+			}
+		}
+	}
+
+	for i := range graph.Nodes {
+		if generation, ok := generations[graph.Nodes[i].ID]; ok {
+			graph.Nodes[i].Generation = &generation
+		}
+	}
+}
@@ -1,8 +1,13 @@
 CREATE TABLE video_games (
-    id INT,
+    id SERIAL,
     title VARCHAR(255),
     notes TEXT,
     multiplayer BOOLEAN,
+    platform VARCHAR(100),
+    status VARCHAR(20),
+    hours_played FLOAT,
+    rating FLOAT,
+    user_uuid CHAR(36),
     PRIMARY KEY (id)
 );
 CREATE TABLE users (
@@ -13,12 +18,14 @@ CREATE TABLE users (
     created TIMESTAMP,
     PRIMARY KEY (uuid)
 );
+CREATE UNIQUE INDEX users_email ON users (email);
 CREATE TABLE theater_movies (
-    id INT,
+    id SERIAL,
     title VARCHAR(255),
     date DATE,
     people_went_with TEXT,
     notes TEXT,
+    user_uuid CHAR(36),
     PRIMARY KEY (id)
 );
 CREATE TABLE watched_movies (
@@ -26,7 +33,10 @@ CREATE TABLE watched_movies (
     rating VARCHAR(50),
     tier VARCHAR(50),
     notes TEXT,
-    PRIMARY KEY (title)
+    watched_date DATE,
+    position INT,
+    user_uuid CHAR(36),
+    CONSTRAINT watched_movies_owner_key UNIQUE (user_uuid, title)
 );
 CREATE TABLE travel (
     title VARCHAR(255),
@@ -34,7 +44,10 @@ CREATE TABLE travel (
     people_went_with TEXT,
     notes TEXT,
     dates DATE,
-    id INT,
+    id SERIAL,
+    start_date DATE,
+    end_date DATE,
+    user_uuid CHAR(36),
     PRIMARY KEY (id)
 );
 CREATE TABLE tv_shows (
@@ -43,7 +56,8 @@ CREATE TABLE tv_shows (
     notes TEXT,
     seasons_watched TEXT,
     childhood_show BOOLEAN,
-    PRIMARY KEY (title)
+    user_uuid CHAR(36),
+    CONSTRAINT tv_shows_owner_key UNIQUE (user_uuid, title)
 );
 CREATE TABLE books (
     title VARCHAR(255),
@@ -51,10 +65,13 @@ CREATE TABLE books (
     author VARCHAR(255),
     rating FLOAT,
     series VARCHAR(255),
+    owned BOOLEAN,
     pages INT,
     series_sequence INT,
     finished BOOLEAN,
-    PRIMARY KEY (title)
+    current_page INT,
+    user_uuid CHAR(36),
+    CONSTRAINT books_owner_key UNIQUE (user_uuid, title)
 );
 CREATE TABLE food_places (
     name VARCHAR(255),
@@ -62,15 +79,21 @@ CREATE TABLE food_places (
     location VARCHAR(255),
     notes TEXT,
     category VARCHAR(50),
-    PRIMARY KEY (name)
+    latitude FLOAT,
+    longitude FLOAT,
+    wishlist BOOLEAN,
+    location_id INT,
+    user_uuid CHAR(36),
+    CONSTRAINT food_places_owner_key UNIQUE (user_uuid, name)
 );
 CREATE TABLE life_events (
-    id INT,
+    id SERIAL,
     title VARCHAR(255),
     month INT,
     day INT,
     year INT,
     notes TEXT,
+    user_uuid CHAR(36),
     PRIMARY KEY (id)
 );
 CREATE TABLE concerts (
@@ -78,10 +101,11 @@ CREATE TABLE concerts (
     artists TEXT,
     notes TEXT,
     people_went_with TEXT,
-    PRIMARY KEY (date)
+    user_uuid CHAR(36),
+    CONSTRAINT concerts_owner_key UNIQUE (user_uuid, date)
 );
 CREATE TABLE people (
-    id INT,
+    id SERIAL,
     first VARCHAR(255),
     middle VARCHAR(255),
     last VARCHAR(255),
@@ -93,22 +117,194 @@ CREATE TABLE people (
     email VARCHAR(255),
     category VARCHAR(50),
     notes TEXT,
+    contact_every_days INT,
+    user_uuid CHAR(36),
     PRIMARY KEY (id)
 );
 CREATE TABLE random_memories (
-    id INT,
+    id SERIAL,
     date DATE,
     notes TEXT,
     involved_people TEXT,
+    user_uuid CHAR(36),
     PRIMARY KEY (id)
-)
+);
 CREATE TABLE journal_entries (
-
-id INTEGER PRIMARY KEY AUTOINCREMENT,
-created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-entry text NOT NULL,
-title varchar(255)
-
-);
-
-
+    id SERIAL PRIMARY KEY,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    entry TEXT NOT NULL,
+    title VARCHAR(255),
+    tags TEXT,
+    photos TEXT,
+    user_uuid CHAR(36)
+);
+CREATE TABLE concert_people (
+    concert_date DATE,
+    person_id INT,
+    user_uuid CHAR(36),
+    PRIMARY KEY (concert_date, person_id),
+    CONSTRAINT concert_people_owner_fkey FOREIGN KEY (user_uuid, concert_date) REFERENCES concerts(user_uuid, date) ON UPDATE CASCADE ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE travel_people (
+    travel_id INT,
+    person_id INT,
+    PRIMARY KEY (travel_id, person_id),
+    FOREIGN KEY (travel_id) REFERENCES travel(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE theater_movie_people (
+    theater_movie_id INT,
+    person_id INT,
+    PRIMARY KEY (theater_movie_id, person_id),
+    FOREIGN KEY (theater_movie_id) REFERENCES theater_movies(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE memory_people (
+    memory_id INT,
+    person_id INT,
+    PRIMARY KEY (memory_id, person_id),
+    FOREIGN KEY (memory_id) REFERENCES random_memories(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE migrations (
+    name VARCHAR(255),
+    applied TIMESTAMP,
+    PRIMARY KEY (name)
+);
+CREATE TABLE comparisons (
+    id SERIAL PRIMARY KEY,
+    category VARCHAR(50) NOT NULL,
+    winner VARCHAR(255) NOT NULL,
+    loser VARCHAR(255) NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    user_uuid CHAR(36)
+);
+CREATE TABLE elo_scores (
+    category VARCHAR(50),
+    title VARCHAR(255),
+    score FLOAT NOT NULL,
+    comparisons INT NOT NULL DEFAULT 0,
+    user_uuid CHAR(36),
+    CONSTRAINT elo_scores_owner_key UNIQUE (user_uuid, category, title)
+);
+CREATE TABLE trip_places (
+    id SERIAL PRIMARY KEY,
+    trip_id INT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    latitude FLOAT,
+    longitude FLOAT,
+    FOREIGN KEY (trip_id) REFERENCES travel(id) ON DELETE CASCADE
+);
+CREATE TABLE files (
+    id SERIAL PRIMARY KEY,
+    bytes BYTEA NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    created TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    latitude FLOAT,
+    longitude FLOAT,
+    taken TIMESTAMP,
+    metadata_version INT,
+    user_uuid CHAR(36)
+);
+CREATE TABLE reading_sessions (
+    id SERIAL PRIMARY KEY,
+    book_title VARCHAR(255) NOT NULL,
+    date DATE NOT NULL,
+    pages_read INT NOT NULL DEFAULT 0,
+    minutes INT NOT NULL DEFAULT 0,
+    user_uuid CHAR(36),
+    CONSTRAINT reading_sessions_owner_fkey FOREIGN KEY (user_uuid, book_title) REFERENCES books(user_uuid, title) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE reading_goals (
+    year INT,
+    target INT NOT NULL,
+    unit VARCHAR(10) NOT NULL,
+    user_uuid CHAR(36),
+    CONSTRAINT reading_goals_owner_key UNIQUE (user_uuid, year)
+);
+CREATE TABLE tv_seasons (
+    show_title VARCHAR(255),
+    season INT,
+    user_uuid CHAR(36),
+    CONSTRAINT tv_seasons_owner_key UNIQUE (user_uuid, show_title, season),
+    CONSTRAINT tv_seasons_owner_fkey FOREIGN KEY (user_uuid, show_title) REFERENCES tv_shows(user_uuid, title) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE tv_episodes (
+    show_title VARCHAR(255),
+    season INT,
+    episode INT,
+    title VARCHAR(255),
+    watched_date DATE,
+    user_uuid CHAR(36),
+    CONSTRAINT tv_episodes_owner_key UNIQUE (user_uuid, show_title, season, episode),
+    CONSTRAINT tv_episodes_owner_fkey FOREIGN KEY (user_uuid, show_title, season) REFERENCES tv_seasons(user_uuid, show_title, season) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE food_visits (
+    id SERIAL PRIMARY KEY,
+    place_name VARCHAR(255) NOT NULL,
+    date DATE,
+    cost FLOAT,
+    notes TEXT,
+    user_uuid CHAR(36),
+    CONSTRAINT food_visits_owner_fkey FOREIGN KEY (user_uuid, place_name) REFERENCES food_places(user_uuid, name) ON UPDATE CASCADE ON DELETE CASCADE
+);
+CREATE TABLE food_visit_dishes (
+    id SERIAL PRIMARY KEY,
+    visit_id INT NOT NULL,
+    position INT NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    rating FLOAT,
+    FOREIGN KEY (visit_id) REFERENCES food_visits(id) ON DELETE CASCADE
+);
+CREATE TABLE food_visit_people (
+    visit_id INT,
+    person_id INT,
+    PRIMARY KEY (visit_id, person_id),
+    FOREIGN KEY (visit_id) REFERENCES food_visits(id) ON DELETE CASCADE,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE locations (
+    id SERIAL PRIMARY KEY,
+    city VARCHAR(255) NOT NULL,
+    region VARCHAR(255) NOT NULL DEFAULT '',
+    country VARCHAR(255) NOT NULL DEFAULT '',
+    name_key VARCHAR(255) NOT NULL UNIQUE
+);
+CREATE TABLE gift_ideas (
+    person_id INT NOT NULL,
+    position INT NOT NULL,
+    idea VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    occasion VARCHAR(255),
+    price FLOAT,
+    PRIMARY KEY (person_id, position),
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE relationships (
+    id SERIAL PRIMARY KEY,
+    person_id INT NOT NULL,
+    related_id INT,
+    type VARCHAR(20) NOT NULL,
+    notes TEXT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE,
+    FOREIGN KEY (related_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX relationships_edge ON relationships (person_id, COALESCE(related_id, 0), type);
+CREATE TABLE interactions (
+    id SERIAL PRIMARY KEY,
+    person_id INT NOT NULL,
+    date DATE NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    notes TEXT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE CASCADE
+);
+CREATE TABLE sessions (
+    token_hash CHAR(64),
+    user_uuid CHAR(36) NOT NULL,
+    created TIMESTAMP,
+    expires TIMESTAMP NOT NULL,
+    PRIMARY KEY (token_hash),
+    FOREIGN KEY (user_uuid) REFERENCES users(uuid) ON DELETE CASCADE
+);
@@ -0,0 +1,588 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	. "memories/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// validDate reports whether value is empty or a YYYY-MM-DD date
+func validDate(value string) bool {
+	if value == "" {
+		return true
+	}
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+// validateMovie tidies up a movie from a request body and returns a message describing
+// the first problem found, or an empty string if the movie can be saved
+func validateMovie(movie *Movie) string {
+	movie.Title = strings.TrimSpace(movie.Title)
+	movie.Tier = strings.ToUpper(strings.TrimSpace(movie.Tier))
+
+	if movie.Title == "" {
+		return "Title is required"
+	}
+	if movie.Tier != "" && !ValidMovieTier(movie.Tier) {
+		return "Invalid tier"
+	}
+	if !validDate(movie.WatchedDate) {
+		return "WatchedDate must be YYYY-MM-DD"
+	}
+	return ""
+}
+
+// yearRange reads the year, from and to query parameters used by the log endpoints.
+// year selects a single year and takes precedence; 0 means that end of the range is open.
+func yearRange(c *gin.Context) (int, int, bool) {
+	var years [3]int
+	for i, key := range []string{"year", "from", "to"} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		year, err := strconv.Atoi(value)
+		if err != nil || year < 1 || year > 9999 {
+			return 0, 0, false
+		}
+		years[i] = year
+	}
+
+	if years[0] > 0 {
+		return years[0], years[0], true
+	}
+	return years[1], years[2], true
+}
+
+// validateTheaterMovie tidies up a theater movie from a request body and returns a message
+// describing the first problem found, or an empty string if it can be saved
+func validateTheaterMovie(movie *TheaterMovie) string {
+	movie.Title = strings.TrimSpace(movie.Title)
+
+	if movie.Title == "" {
+		return "Title is required"
+	}
+	if !validDate(movie.Date) {
+		return "Date must be YYYY-MM-DD"
+	}
+	return ""
+}
+
+// validateTrip tidies up a trip from a request body and returns a message describing
+// the first problem found, or an empty string if it can be saved
+func validateTrip(trip *Trip) string {
+	trip.Title = strings.TrimSpace(trip.Title)
+
+	if trip.Title == "" {
+		return "Title is required"
+	}
+	if !validDate(trip.StartDate) || !validDate(trip.EndDate) {
+		return "StartDate and EndDate must be YYYY-MM-DD"
+	}
+	if trip.StartDate == "" && trip.EndDate != "" {
+		return "EndDate requires a StartDate"
+	}
+	// YYYY-MM-DD strings sort the same way as the dates they hold
+	if trip.EndDate != "" && trip.EndDate < trip.StartDate {
+		return "EndDate is before StartDate"
+	}
+
+	for i := range trip.Places {
+		place := &trip.Places[i]
+		place.Name = strings.TrimSpace(place.Name)
+		if place.Name == "" {
+			return "Every place needs a Name"
+		}
+		if problem := validateCoordinates(place.Latitude, place.Longitude); problem != "" {
+			return problem
+		}
+	}
+	return ""
+}
+
+// validateCoordinates checks an optional latitude/longitude pair
+func validateCoordinates(latitude, longitude *float64) string {
+	if (latitude == nil) != (longitude == nil) {
+		return "Latitude and Longitude must be given together"
+	}
+	if latitude != nil && (*latitude < -90 || *latitude > 90 || *longitude < -180 || *longitude > 180) {
+		return "Coordinates are out of range"
+	}
+	return ""
+}
+
+// validateLifeEvent tidies up a life event from a request body and returns a message
+// describing the first problem found, or an empty string if it can be saved
+func validateLifeEvent(event *LifeEvent) string {
+	event.Title = strings.TrimSpace(event.Title)
+
+	if event.Title == "" {
+		return "Title is required"
+	}
+	if event.Year < 1 || event.Year > 9999 {
+		return "Year is required"
+	}
+	if event.Month < 0 || event.Month > 12 {
+		return "Month must be between 1 and 12"
+	}
+	if event.Day != 0 {
+		if event.Month == 0 {
+			return "Day requires a Month"
+		}
+		// time.Date normalizes out of range days into the next month
+		date := time.Date(event.Year, time.Month(event.Month), event.Day, 0, 0, 0, 0, time.UTC)
+		if event.Day < 0 || date.Day() != event.Day {
+			return "Day is not in that month"
+		}
+	}
+	return ""
+}
+
+// Page sizes for the timeline
+const (
+	defaultTimelineLimit = 50
+	maxTimelineLimit     = 200
+)
+
+// timelineQuery reads the from, to, types, limit and offset query parameters of the timeline
+// and returns a message describing the first problem found, or an empty string
+func timelineQuery(c *gin.Context) (TimelineQuery, string) {
+	query := TimelineQuery{
+		From:  c.Query("from"),
+		To:    c.Query("to"),
+		Limit: defaultTimelineLimit,
+	}
+
+	if !validDate(query.From) || !validDate(query.To) {
+		return query, "from and to must be YYYY-MM-DD"
+	}
+	if query.From != "" && query.To != "" && query.To < query.From {
+		return query, "to is before from"
+	}
+
+	for _, typ := range strings.Split(c.Query("types"), ",") {
+		typ = strings.TrimSpace(typ)
+		if typ == "" {
+			continue
+		}
+		if !slices.Contains(TimelineTypes, typ) {
+			return query, "Unknown type: " + typ
+		}
+		query.Types = append(query.Types, typ)
+	}
+
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxTimelineLimit {
+			return query, fmt.Sprintf("limit must be between 1 and %d", maxTimelineLimit)
+		}
+		query.Limit = limit
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return query, "offset must not be negative"
+		}
+		query.Offset = offset
+	}
+
+	return query, ""
+}
+
+// parseMonthDay reads an MM-DD calendar day
+func parseMonthDay(value string) (int, int, bool) {
+	// Parsed in a leap year so February 29 is a real day
+	day, err := time.Parse("2006-01-02", "2000-"+value)
+	if err != nil {
+		return 0, 0, false
+	}
+	return int(day.Month()), day.Day(), true
+}
+
+// validateMemory tidies up a memory from a request body and returns a message describing
+// the first problem found, or an empty string if it can be saved
+func validateMemory(memory *Memory) string {
+	memory.Notes = strings.TrimSpace(memory.Notes)
+
+	if memory.Notes == "" {
+		return "Notes are required"
+	}
+	if !validDate(memory.Date) {
+		return "Date must be YYYY-MM-DD"
+	}
+	return ""
+}
+
+// validateVideoGame tidies up a video game from a request body and returns a message
+// describing the first problem found, or an empty string if it can be saved
+func validateVideoGame(game *VideoGame) string {
+	game.Title = strings.TrimSpace(game.Title)
+	game.Platform = strings.TrimSpace(game.Platform)
+	game.Status = strings.ToLower(strings.TrimSpace(game.Status))
+	if game.Status == "" {
+		game.Status = "backlog"
+	}
+
+	if game.Title == "" {
+		return "Title is required"
+	}
+	if !ValidGameStatus(game.Status) {
+		return "Status must be one of " + strings.Join(GameStatuses, ", ")
+	}
+	if game.HoursPlayed < 0 {
+		return "HoursPlayed must not be negative"
+	}
+	if game.Rating < 0 || game.Rating > 10 {
+		return "Rating must be between 0 and 10"
+	}
+	return ""
+}
+
+// validateBook tidies up a book from a request body and returns a message describing
+// the first problem found, or an empty string if it can be saved
+func validateBook(book *Book) string {
+	book.Title = strings.TrimSpace(book.Title)
+	book.Author = strings.TrimSpace(book.Author)
+	book.Series = strings.TrimSpace(book.Series)
+
+	if book.Title == "" {
+		return "Title is required"
+	}
+	if book.Rating < 0 || book.Rating > 10 {
+		return "Rating must be between 0 and 10"
+	}
+	if book.Pages < 0 {
+		return "Pages must not be negative"
+	}
+	if book.SeriesSequence < 0 {
+		return "SeriesSequence must not be negative"
+	}
+	if book.SeriesSequence > 0 && book.Series == "" {
+		return "SeriesSequence requires a Series"
+	}
+	if !validDate(book.DateFinished) {
+		return "DateFinished must be YYYY-MM-DD"
+	}
+	if book.CurrentPage < 0 {
+		return "CurrentPage must not be negative"
+	}
+	if book.Pages > 0 && book.CurrentPage > book.Pages {
+		return "CurrentPage must not be past the last page"
+	}
+	// A finish date means the book was finished
+	if book.DateFinished != "" {
+		book.Finished = true
+	}
+	return ""
+}
+
+// boolQuery reads an optional true/false query parameter, returning nil when it is absent
+func boolQuery(c *gin.Context, key string) (*bool, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, true
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// validateReadingSession checks a reading session from a request body and returns a message
+// describing the first problem found, or an empty string if it can be saved
+func validateReadingSession(session *ReadingSession) string {
+	session.Date = strings.TrimSpace(session.Date)
+
+	if session.Date == "" || !validDate(session.Date) {
+		return "Date must be YYYY-MM-DD"
+	}
+	if session.PagesRead < 0 || session.Minutes < 0 {
+		return "PagesRead and Minutes must not be negative"
+	}
+	if session.PagesRead == 0 && session.Minutes == 0 {
+		return "A session needs PagesRead or Minutes"
+	}
+	return ""
+}
+
+// validateReadingGoal tidies up a reading goal from a request body and returns a message
+// describing the first problem found, or an empty string if it can be saved
+func validateReadingGoal(goal *ReadingGoal) string {
+	goal.Unit = strings.ToLower(strings.TrimSpace(goal.Unit))
+
+	if goal.Year < 1 || goal.Year > 9999 {
+		return "Invalid year"
+	}
+	if goal.Target <= 0 {
+		return "Target must be positive"
+	}
+	if !slices.Contains(ReadingGoalUnits, goal.Unit) {
+		return "Unit must be one of " + strings.Join(ReadingGoalUnits, ", ")
+	}
+	return ""
+}
+
+// validateTVShow tidies up a TV show from a request body and returns a message describing
+// the first problem found, or an empty string if it can be saved
+func validateTVShow(show *TVShow) string {
+	show.Title = strings.TrimSpace(show.Title)
+	show.Notes = strings.TrimSpace(show.Notes)
+	show.SeasonsWatched = strings.TrimSpace(show.SeasonsWatched)
+
+	if show.Title == "" {
+		return "Title is required"
+	}
+	return ""
+}
+
+// validateTVSeason tidies up a season from a request body and returns a message describing
+// the first problem found, or an empty string if it can be saved. Episodes without a number
+// are numbered by their position.
+func validateTVSeason(season *TVSeason) string {
+	if season.Number < 1 {
+		return "Invalid season number"
+	}
+	if len(season.Episodes) == 0 {
+		return "A season needs at least one episode"
+	}
+	for i := range season.Episodes {
+		episode := &season.Episodes[i]
+		if episode.Number == 0 {
+			episode.Number = i + 1
+		}
+		if episode.Number != i+1 {
+			return "Episodes must be numbered from 1 in order"
+		}
+		episode.Season = season.Number
+		episode.Title = strings.TrimSpace(episode.Title)
+		episode.WatchedDate = ""
+	}
+	return ""
+}
+
+// positiveParam reads a path parameter that must be a positive whole number
+func positiveParam(c *gin.Context, key string) (int, bool) {
+	var value int
+	_, err := fmt.Sscanf(c.Param(key), "%d", &value)
+	return value, err == nil && value > 0
+}
+
+// watchedDate reads the optional {"Date": "YYYY-MM-DD"} body sent when marking something
+// watched, defaulting to today. Returns a message describing any problem with the body.
+func watchedDate(c *gin.Context) (string, string) {
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return "", "Invalid request"
+	}
+
+	var body struct {
+		Date string `json:"Date"`
+	}
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			return "", "Error parsing body"
+		}
+	}
+
+	body.Date = strings.TrimSpace(body.Date)
+	if body.Date == "" {
+		return time.Now().Format("2006-01-02"), ""
+	}
+	if !validDate(body.Date) {
+		return "", "Date must be YYYY-MM-DD"
+	}
+	return body.Date, ""
+}
+
+// validateFoodPlace tidies up a food place from a request body and returns a message
+// describing the first problem found, or an empty string if it can be saved
+func validateFoodPlace(place *FoodPlace) string {
+	place.Name = strings.TrimSpace(place.Name)
+	place.Location = strings.TrimSpace(place.Location)
+	place.Type = strings.TrimSpace(place.Type)
+	place.Category = strings.TrimSpace(place.Category)
+	place.Notes = strings.TrimSpace(place.Notes)
+
+	if place.Name == "" {
+		return "Name is required"
+	}
+	return validateCoordinates(place.Latitude, place.Longitude)
+}
+
+// validateFoodVisit tidies up a visit from a request body and returns a message describing
+// the first problem found, or an empty string if it can be saved
+func validateFoodVisit(visit *FoodVisit) string {
+	visit.Date = strings.TrimSpace(visit.Date)
+	visit.Notes = strings.TrimSpace(visit.Notes)
+
+	if visit.Date == "" || !validDate(visit.Date) {
+		return "Date must be YYYY-MM-DD"
+	}
+	if visit.Cost < 0 {
+		return "Cost must not be negative"
+	}
+	for i := range visit.Dishes {
+		dish := &visit.Dishes[i]
+		dish.Name = strings.TrimSpace(dish.Name)
+		if dish.Name == "" {
+			return "Every dish needs a Name"
+		}
+		if dish.Rating != nil && (*dish.Rating < 0 || *dish.Rating > 10) {
+			return "Dish ratings must be between 0 and 10"
+		}
+	}
+	return ""
+}
+
+// isDayOfMonth reports whether the month has the day in the given year. A year of 0 is
+// unknown, so February 29th is allowed.
+func isDayOfMonth(year, month, day int) bool {
+	if year == 0 {
+		year = 2000
+	}
+	// time.Date normalizes out of range days into the next month
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	return day > 0 && date.Day() == day
+}
+
+// validatePerson tidies up a person and their gift ideas from a request body and returns a
+// message describing the first problem found, or an empty string if it can be saved
+func validatePerson(person *Person) string {
+	person.First = strings.TrimSpace(person.First)
+	person.Middle = strings.TrimSpace(person.Middle)
+	person.Last = strings.TrimSpace(person.Last)
+	person.Address = strings.TrimSpace(person.Address)
+	person.Email = strings.TrimSpace(person.Email)
+	person.Category = strings.TrimSpace(person.Category)
+	person.Notes = strings.TrimSpace(person.Notes)
+
+	if person.First == "" && person.Last == "" {
+		return "First or Last name is required"
+	}
+	if person.BirthMonth < 0 || person.BirthMonth > 12 {
+		return "BirthMonth must be between 1 and 12"
+	}
+	if person.BirthYear < 0 || person.BirthYear > 9999 {
+		return "Invalid BirthYear"
+	}
+	if person.BirthDay != 0 {
+		if person.BirthMonth == 0 {
+			return "BirthDay needs a BirthMonth"
+		}
+		if !isDayOfMonth(person.BirthYear, person.BirthMonth, person.BirthDay) {
+			return "BirthDay is not in that month"
+		}
+	}
+	if person.Email != "" && !strings.Contains(person.Email, "@") {
+		return "Email must be an email address"
+	}
+	if person.ContactEveryDays < 0 {
+		return "ContactEveryDays can't be negative"
+	}
+
+	for i := range person.GiftIdeas {
+		gift := &person.GiftIdeas[i]
+		gift.Idea = strings.TrimSpace(gift.Idea)
+		gift.Status = strings.ToLower(strings.TrimSpace(gift.Status))
+		gift.Occasion = strings.TrimSpace(gift.Occasion)
+
+		if gift.Idea == "" {
+			return "Every gift idea needs an Idea"
+		}
+		if gift.Status == "" {
+			gift.Status = GiftStatuses[0]
+		}
+		if !slices.Contains(GiftStatuses, gift.Status) {
+			return "Gift Status must be one of " + strings.Join(GiftStatuses, ", ")
+		}
+		if gift.Price != nil && *gift.Price < 0 {
+			return "Gift Price can't be negative"
+		}
+	}
+	return ""
+}
+
+// validateInteraction tidies up an interaction from a request body, dating it today when no
+// date is given, and returns a message describing the first problem found, or an empty
+// string if it can be saved
+func validateInteraction(interaction *Interaction, now time.Time) string {
+	interaction.Kind = strings.ToLower(strings.TrimSpace(interaction.Kind))
+	interaction.Date = strings.TrimSpace(interaction.Date)
+	interaction.Notes = strings.TrimSpace(interaction.Notes)
+
+	if !slices.Contains(InteractionKinds, interaction.Kind) {
+		return "Kind must be one of " + strings.Join(InteractionKinds, ", ")
+	}
+	if interaction.Date == "" {
+		interaction.Date = now.Format("2006-01-02")
+	}
+	if !validDate(interaction.Date) {
+		return "Date must be YYYY-MM-DD"
+	}
+	return ""
+}
+
+// validateRelationship tidies up a relationship from a request body and returns a message
+// describing the first problem found, or an empty string if it can be saved. Relationships
+// between two people are stored one way round so each is only recorded once.
+func validateRelationship(relationship *Relationship) string {
+	relationship.Type = strings.ToLower(strings.TrimSpace(relationship.Type))
+	relationship.Notes = strings.TrimSpace(relationship.Notes)
+
+	t, ok := relationshipType(relationship.Type)
+	if !ok {
+		names := make([]string, len(RelationshipTypes))
+		for i, t := range RelationshipTypes {
+			names[i] = t.Name
+		}
+		return "Type must be one of " + strings.Join(names, ", ")
+	}
+	if relationship.PersonID <= 0 || relationship.RelatedID < 0 {
+		return "Unknown person ID"
+	}
+	if relationship.PersonID == relationship.RelatedID {
+		return "A person can't be related to themselves"
+	}
+
+	if relationship.RelatedID == 0 {
+		return ""
+	}
+	if relationship.Type == "child" {
+		relationship.PersonID, relationship.RelatedID = relationship.RelatedID, relationship.PersonID
+		relationship.Type = t.Inverse
+	}
+	if !t.Directed && relationship.PersonID > relationship.RelatedID {
+		relationship.PersonID, relationship.RelatedID = relationship.RelatedID, relationship.PersonID
+	}
+	return ""
+}
+
+// minPasswordLength is the shortest password accepted when registering or changing passwords
+const minPasswordLength = 8
+
+// validateCredentials tidies up the email and password from a registration request and
+// returns a message describing the first problem found, or an empty string if they're usable
+func validateCredentials(credentials *Credentials) string {
+	credentials.Email = strings.ToLower(strings.TrimSpace(credentials.Email))
+
+	if credentials.Email == "" {
+		return "Email is required"
+	}
+	if !strings.Contains(credentials.Email, "@") || strings.ContainsAny(credentials.Email, " \t\r\n") {
+		return "Invalid email"
+	}
+	if len(credentials.Password) < minPasswordLength {
+		return fmt.Sprintf("Password must be at least %d characters", minPasswordLength)
+	}
+	return ""
+}
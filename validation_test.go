@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	. "memories/model"
+)
+
+func TestValidatePersonBirthday(t *testing.T) {
+	tests := []struct {
+		name             string
+		year, month, day int
+		wantProblem      bool
+	}{
+		{"no birthday", 0, 0, 0, false},
+		{"month only", 0, 4, 0, false},
+		{"full date", 1990, 4, 23, false},
+		{"last day of month", 1990, 4, 30, false},
+		{"leap day in a leap year", 2000, 2, 29, false},
+		{"leap day without a year", 0, 2, 29, false},
+		{"leap day in a common year", 1990, 2, 29, true},
+		{"February 30th", 0, 2, 30, true},
+		{"April 31st", 1990, 4, 31, true},
+		{"day 32", 0, 1, 32, true},
+		{"negative day", 0, 1, -1, true},
+		{"day without a month", 0, 0, 12, true},
+		{"month 13", 0, 13, 1, true},
+	}
+	for _, test := range tests {
+		person := Person{First: "Ada", BirthYear: test.year, BirthMonth: test.month, BirthDay: test.day}
+		problem := validatePerson(&person)
+		if (problem != "") != test.wantProblem {
+			t.Errorf("%s: validatePerson() = %q, want a problem: %v", test.name, problem, test.wantProblem)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	. "memories/model"
+)
+
+// parseVCards reads the contacts in a vCard 3.0 or 4.0 file (RFC 2426, RFC 6350) as people.
+// Properties without a Person field are ignored.
+func parseVCards(data string) []Person {
+	// Unfold continuation lines, which start with a space or tab
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+
+	var people []Person
+	var person *Person
+	var fullName string
+	for _, line := range strings.Split(data, "\n") {
+		name, value, ok := splitContentLine(line)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			person = &Person{}
+			fullName = ""
+		case person == nil:
+			continue
+		case name == "END" && strings.EqualFold(value, "VCARD"):
+			if person.First == "" && person.Last == "" {
+				person.First, person.Middle, person.Last = splitFullName(fullName)
+			}
+			people = append(people, *person)
+			person = nil
+		case name == "N":
+			parts := splitEscaped(value, ';')
+			person.Last = componentAt(parts, 0)
+			person.First = componentAt(parts, 1)
+			person.Middle = componentAt(parts, 2)
+		case name == "FN":
+			fullName = unescapeContentText(value)
+		case name == "EMAIL":
+			if person.Email == "" {
+				person.Email = unescapeContentText(value)
+			}
+		case name == "ADR":
+			if person.Address == "" {
+				// Skip the post office box and extended address, keep street through country
+				parts := splitEscaped(value, ';')
+				var address []string
+				for i := 2; i < len(parts); i++ {
+					if part := componentAt(parts, i); part != "" {
+						address = append(address, part)
+					}
+				}
+				person.Address = strings.Join(address, ", ")
+			}
+		case name == "BDAY":
+			person.BirthYear, person.BirthMonth, person.BirthDay = parseVCardDate(value)
+		case name == "NOTE":
+			person.Notes = unescapeContentText(value)
+		case name == "CATEGORIES":
+			if person.Category == "" {
+				person.Category = componentAt(splitEscaped(value, ','), 0)
+			}
+		}
+	}
+	return people
+}
+
+// splitContentLine splits "group.NAME;PARAM=x:value" into its upper case name and value
+func splitContentLine(line string) (string, string, bool) {
+	// Parameter values may be quoted and contain colons
+	quoted := false
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ':' && !quoted:
+			name, _, _ := strings.Cut(line[:i], ";")
+			if dot := strings.LastIndex(name, "."); dot >= 0 {
+				name = name[dot+1:]
+			}
+			return strings.ToUpper(strings.TrimSpace(name)), strings.TrimSpace(line[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// splitEscaped splits a structured value on sep, ignoring separators escaped with a backslash.
+// The pieces are left escaped.
+func splitEscaped(value string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, value[start:])
+}
+
+// componentAt unescapes the ith component of a structured value, or returns "" if it's missing
+func componentAt(parts []string, i int) string {
+	if i >= len(parts) {
+		return ""
+	}
+	return strings.TrimSpace(unescapeContentText(parts[i]))
+}
+
+// unescapeContentText reverses escapeContentText
+func unescapeContentText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		if value[i] == 'n' || value[i] == 'N' {
+			b.WriteByte('\n')
+		} else {
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// splitFullName guesses first, middle and last names from a formatted name
+func splitFullName(name string) (first, middle, last string) {
+	words := strings.Fields(unescapeContentText(name))
+	switch len(words) {
+	case 0:
+	case 1:
+		first = words[0]
+	default:
+		first = words[0]
+		middle = strings.Join(words[1:len(words)-1], " ")
+		last = words[len(words)-1]
+	}
+	return first, middle, last
+}
+
+// parseVCardDate reads a birthday like 1990-04-23, 19900423 or, without a year, --0423 or
+// --04-23. Any time of day is ignored, and parts that can't be read or a day the month
+// doesn't have are 0.
+func parseVCardDate(value string) (year, month, day int) {
+	value, _, _ = strings.Cut(value, "T")
+	noYear := strings.HasPrefix(value, "--")
+	digits := strings.ReplaceAll(value, "-", "")
+	if _, err := strconv.Atoi(digits); err != nil {
+		return 0, 0, 0
+	}
+
+	switch {
+	case noYear && len(digits) == 4:
+		month, _ = strconv.Atoi(digits[:2])
+		day, _ = strconv.Atoi(digits[2:])
+	case !noYear && len(digits) == 8:
+		year, _ = strconv.Atoi(digits[:4])
+		month, _ = strconv.Atoi(digits[4:6])
+		day, _ = strconv.Atoi(digits[6:])
+	case !noYear && len(digits) == 4:
+		year, _ = strconv.Atoi(digits)
+	}
+	if month < 1 || month > 12 {
+		return year, 0, 0
+	}
+	if day != 0 && !isDayOfMonth(year, month, day) {
+		return year, month, 0
+	}
+	return year, month, day
+}
+
+// findDuplicatePerson returns the id of an existing person with the same email address or the
+// same first and last name, along with which one matched. The reason is empty when there's none.
+func findDuplicatePerson(person Person, people []Person) (int, string) {
+	for _, existing := range people {
+		if person.Email != "" && strings.EqualFold(person.Email, existing.Email) {
+			return existing.ID, "Same email as " + personName(existing)
+		}
+	}
+	for _, existing := range people {
+		if strings.EqualFold(person.First, existing.First) && strings.EqualFold(person.Last, existing.Last) {
+			return existing.ID, "Same name as an existing person"
+		}
+	}
+	return 0, ""
+}
+
+// peopleVCards exports people as vCard 3.0, the version phones import most reliably.
+// Birthdays without a year use the --MM-DD form most apps accept.
+func peopleVCards(people []Person) string {
+	var b strings.Builder
+	for _, person := range people {
+		writeContentLine(&b, "BEGIN:VCARD")
+		writeContentLine(&b, "VERSION:3.0")
+		writeContentLine(&b, fmt.Sprintf("UID:person-%d@memories", person.ID))
+		writeContentLine(&b, fmt.Sprintf("N:%s;%s;%s;;", escapeContentText(person.Last), escapeContentText(person.First), escapeContentText(person.Middle)))
+		writeContentLine(&b, "FN:"+escapeContentText(personName(person)))
+		if person.Email != "" {
+			writeContentLine(&b, "EMAIL;TYPE=INTERNET:"+escapeContentText(person.Email))
+		}
+		if person.Address != "" {
+			// The address is free text, so it all goes in the street part
+			writeContentLine(&b, fmt.Sprintf("ADR:;;%s;;;;", escapeContentText(person.Address)))
+		}
+		if person.BirthMonth > 0 && person.BirthDay > 0 {
+			if person.BirthYear > 0 {
+				writeContentLine(&b, fmt.Sprintf("BDAY:%04d-%02d-%02d", person.BirthYear, person.BirthMonth, person.BirthDay))
+			} else {
+				writeContentLine(&b, fmt.Sprintf("BDAY:--%02d-%02d", person.BirthMonth, person.BirthDay))
+			}
+		}
+		if person.Category != "" {
+			writeContentLine(&b, "CATEGORIES:"+escapeContentText(person.Category))
+		}
+		if person.Notes != "" {
+			writeContentLine(&b, "NOTE:"+escapeContentText(person.Notes))
+		}
+		writeContentLine(&b, "END:VCARD")
+	}
+	return b.String()
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestParseVCardDate(t *testing.T) {
+	tests := []struct {
+		value            string
+		year, month, day int
+	}{
+		{"1990-04-23", 1990, 4, 23},
+		{"19900423", 1990, 4, 23},
+		{"1990-04-23T10:00:00Z", 1990, 4, 23},
+		{"--0423", 0, 4, 23},
+		{"--04-23", 0, 4, 23},
+		{"1990", 1990, 0, 0},
+		{"2000-02-29", 2000, 2, 29},
+		{"--0229", 0, 2, 29},
+		{"1990-02-29", 1990, 2, 0},
+		{"--0230", 0, 2, 0},
+		{"1990-04-31", 1990, 4, 0},
+		{"1990-13-01", 1990, 0, 0},
+		{"April 23rd", 0, 0, 0},
+	}
+	for _, test := range tests {
+		year, month, day := parseVCardDate(test.value)
+		if year != test.year || month != test.month || day != test.day {
+			t.Errorf("parseVCardDate(%q) = %d, %d, %d, want %d, %d, %d", test.value, year, month, day, test.year, test.month, test.day)
+		}
+	}
+}